@@ -37,8 +37,9 @@ func main() {
 	// Initialize dependencies using DI
 	di := di.New(cfg, log)
 
-	// Start background worker
+	// Start background workers
 	di.GetSenderService().StartProcessEvents(context.Background(), 30*time.Second)
+	di.GetFlatService().StartReleaseModerations(context.Background(), cfg.Moderation.CheckPeriod, cfg.Moderation.Timeout)
 
 	// Start server
 	go func() {
@@ -11,10 +11,14 @@ import (
 type Config struct {
 	Env string `yaml:"env" env-default:"local"`
 
-	HTTPServer `yaml:"http_server"`
-	DB         `yaml:"db"`
-	JWT        `yaml:"jwt"`
-	Cache      `yaml:"cache"`
+	HTTPServer    `yaml:"http_server"`
+	DB            `yaml:"db"`
+	JWT           `yaml:"jwt"`
+	Cache         `yaml:"cache"`
+	Storage       `yaml:"storage"`
+	Geocoder      `yaml:"geocoder"`
+	Premoderation `yaml:"premoderation"`
+	Moderation    `yaml:"moderation"`
 }
 
 type JWT struct {
@@ -27,6 +31,31 @@ type Cache struct {
 	TTL time.Duration `yaml:"ttl"`
 }
 
+// Локальное хранилище файлов, раздается HTTP-сервером по префиксу BaseURL.
+// Фотографии квартиры доступны только тем, кому видна квартира
+type Storage struct {
+	Dir     string `yaml:"dir" env-default:"./media"`
+	BaseURL string `yaml:"base_url" env-default:"/media"`
+}
+
+// Путь к YAML-файлу с известными адресами и их координатами.
+// Без него адреса сохраняются как введены, без координат
+type Geocoder struct {
+	AddressesPath string `yaml:"addresses_path"`
+}
+
+// Путь к YAML-файлу с правилами премодерации, без него правила не применяются
+type Premoderation struct {
+	RulesPath string `yaml:"rules_path"`
+}
+
+// Квартира, взятая на модерацию, возвращается в очередь по истечении Timeout.
+// Зависшие модерации проверяются раз в CheckPeriod
+type Moderation struct {
+	Timeout     time.Duration `yaml:"timeout" env-default:"30m"`
+	CheckPeriod time.Duration `yaml:"check_period" env-default:"1m"`
+}
+
 type HTTPServer struct {
 	Address         string        `yaml:"address" env-default:":8080"`
 	Timeout         time.Duration `yaml:"timeout" env-default:"4s"`
@@ -3,14 +3,17 @@ package di
 import (
 	"avito-backend-bootcamp/internal/config"
 	"avito-backend-bootcamp/internal/http/server"
+	"avito-backend-bootcamp/internal/infra/blob"
 	"avito-backend-bootcamp/internal/infra/cache"
 	sender "avito-backend-bootcamp/internal/infra/email"
+	"avito-backend-bootcamp/internal/infra/geocoder"
 	"avito-backend-bootcamp/internal/infra/jwt"
 	"avito-backend-bootcamp/internal/infra/repository/postgres"
 	"avito-backend-bootcamp/internal/service/auth"
 	emailsender "avito-backend-bootcamp/internal/service/email-sender"
 	"avito-backend-bootcamp/internal/service/flat"
 	"avito-backend-bootcamp/internal/service/house"
+	"avito-backend-bootcamp/internal/service/premoderation"
 	sub "avito-backend-bootcamp/internal/service/subscription"
 	dbUtil "avito-backend-bootcamp/pkg/utils/db"
 	"context"
@@ -28,19 +31,22 @@ type Container struct {
 	cfg *config.Config
 	log *slog.Logger
 
-	flatCache   *cache.TTLCache[int64, string]
+	flatCache   *cache.TTLCache[string, string]
 	validator   *validator.Validate
 	jwt         *jwt.Manager
 	emailClient *sender.Sender
+	blobStorage *blob.Local
+	geocoder    house.Geocoder
 	repository  *postgres.Repository
 	db          *sqlx.DB
 	trManager   *manager.Manager
 
-	flatService  *flat.Service
-	houseService *house.Service
-	subService   *sub.Service
-	authService  *auth.Service
-	emailService *emailsender.Service
+	premoderation *premoderation.Engine
+	flatService   *flat.Service
+	houseService  *house.Service
+	subService    *sub.Service
+	authService   *auth.Service
+	emailService  *emailsender.Service
 
 	serverHTTP *server.Server
 }
@@ -77,9 +83,9 @@ func (c *Container) GetDB() *sqlx.DB {
 	})
 }
 
-func (c *Container) GetFlatCache() *cache.TTLCache[int64, string] {
-	return get(&c.flatCache, func() *cache.TTLCache[int64, string] {
-		return cache.NewTTL[int64, string](c.cfg.Cache.TTL)
+func (c *Container) GetFlatCache() *cache.TTLCache[string, string] {
+	return get(&c.flatCache, func() *cache.TTLCache[string, string] {
+		return cache.NewTTL[string, string](c.cfg.Cache.TTL)
 	})
 }
 
@@ -101,6 +107,31 @@ func (c *Container) GetEmailClient() *sender.Sender {
 	})
 }
 
+func (c *Container) GetBlobStorage() *blob.Local {
+	return get(&c.blobStorage, func() *blob.Local {
+		storage, err := blob.NewLocal(c.cfg.Storage.Dir, c.cfg.Storage.BaseURL)
+		if err != nil {
+			panic(err)
+		}
+		return storage
+	})
+}
+
+// GetGeocoder returns the geocoder of house addresses. Known addresses are read
+// from the configured file, without it addresses are kept as entered, without coordinates.
+func (c *Container) GetGeocoder() house.Geocoder {
+	return get(&c.geocoder, func() house.Geocoder {
+		if c.cfg.Geocoder.AddressesPath == "" {
+			return geocoder.Passthrough{}
+		}
+		file, err := geocoder.LoadFile(c.cfg.Geocoder.AddressesPath)
+		if err != nil {
+			panic(err)
+		}
+		return file
+	})
+}
+
 func (c *Container) GetRepository() *postgres.Repository {
 	return get(&c.repository, func() *postgres.Repository {
 		db, err := postgres.New(context.Background(), &c.cfg.DB)
@@ -121,12 +152,31 @@ func (c *Container) GetAuthService() *auth.Service {
 	})
 }
 
+func (c *Container) GetPremoderation() *premoderation.Engine {
+	return get(&c.premoderation, func() *premoderation.Engine {
+		rules := &premoderation.Rules{}
+		if c.cfg.Premoderation.RulesPath != "" {
+			var err error
+			rules, err = premoderation.LoadRules(c.cfg.Premoderation.RulesPath)
+			if err != nil {
+				panic(err)
+			}
+		}
+		return premoderation.New(rules, c.GetRepository())
+	})
+}
+
 func (c *Container) GetFlatService() *flat.Service {
 	return get(&c.flatService, func() *flat.Service {
 		return flat.New(
 			c.log,
 			c.GetRepository(),
 			c.GetRepository(),
+			c.GetRepository(),
+			c.GetRepository(),
+			c.GetRepository(),
+			c.GetBlobStorage(),
+			c.GetPremoderation(),
 			c.GetFlatCache(),
 			c.GetTrManager(),
 		)
@@ -138,6 +188,13 @@ func (c *Container) GetHouseService() *house.Service {
 		return house.New(
 			c.log,
 			c.GetRepository(),
+			c.GetRepository(),
+			c.GetRepository(),
+			c.GetRepository(),
+			c.GetBlobStorage(),
+			c.GetGeocoder(),
+			c.GetFlatCache(),
+			c.GetTrManager(),
 		)
 	})
 }
@@ -174,6 +231,7 @@ func (c *Container) GetHTTPServer() *server.Server {
 			c.GetHouseService(),
 			c.GetSubsciptionService(),
 			c.GetJwtManager(),
+			c.GetBlobStorage(),
 		)
 		if err != nil {
 			panic(err)
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ActorFromContext returns the authenticated user put into the context by the auth middleware.
+func ActorFromContext(ctx context.Context) model.Actor {
+	return model.Actor{
+		ID:   ctx.Value(pkgCtx.KeyUserID).(uuid.UUID),
+		Role: ctx.Value(pkgCtx.KeyUserType).(model.UserType),
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	ClaimFlat(ctx context.Context, moderator model.Actor) (*model.Flat, error)
+}
+
+type claimFlatResponse struct {
+	ID      int64  `json:"id"`
+	HouseID int64  `json:"house_id"`
+	Number  int64  `json:"number"`
+	Price   int64  `json:"price"`
+	Rooms   int64  `json:"rooms"`
+	Status  string `json:"status"`
+	Version int64  `json:"version"`
+}
+
+// HandleClaimFlat берет на модерацию самую старую квартиру из очереди
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleClaimFlat"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Claim the next flat on behalf of the authenticated moderator
+		flat, err := flatService.ClaimFlat(r.Context(), h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to claim flat", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrModerationQueueEmpty) {
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the claimed flat details
+		log.Info("flat claimed for moderation")
+		w.Header().Set("ETag", h.ETag(flat.Version))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, claimFlatResponse{
+			ID:      flat.ID,
+			HouseID: flat.HouseID,
+			Number:  flat.Number,
+			Price:   flat.Price,
+			Status:  string(flat.Status),
+			Version: flat.Version,
+			Rooms:   flat.Rooms,
+		})
+	}
+}
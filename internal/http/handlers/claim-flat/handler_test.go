@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/claim-flat/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testModerator = model.Actor{
+	ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+	Role: model.Moderator,
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test moderator
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testModerator.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testModerator.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Post("/moderation/claim", h)
+
+	return r
+}
+
+func TestHandleClaimFlat(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ClaimFlat(gomock.Any(), testModerator).
+			Return(&model.Flat{ID: 123, HouseID: 456, Price: 1000000, Rooms: 2, Status: model.StatusOnModeration}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/moderation/claim", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response claimFlatResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, claimFlatResponse{
+			ID:      123,
+			HouseID: 456,
+			Price:   1000000,
+			Rooms:   2,
+			Status:  "on_moderation",
+		}, response)
+	})
+
+	t.Run("empty queue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ClaimFlat(gomock.Any(), testModerator).
+			Return(nil, flatPkg.ErrModerationQueueEmpty)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/moderation/claim", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, flatPkg.ErrModerationQueueEmpty.Error(), response.Error)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ClaimFlat(gomock.Any(), testModerator).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/moderation/claim", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// Assert response body
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, handlers.ErrorResponse{
+			Message:   "internal",
+			RequestID: "test",
+			Code:      http.StatusInternalServerError,
+		}, response)
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	CloseFlat(ctx context.Context, ID int64, status model.FlatStatus, actor model.Actor) (*model.Flat, error)
+}
+
+type closeFlatRequest struct {
+	Status string `json:"status" validate:"required,oneof=archived sold"`
+}
+
+type closeFlatResponse struct {
+	ID      int64  `json:"id"`
+	HouseID int64  `json:"house_id"`
+	Number  int64  `json:"number"`
+	Price   int64  `json:"price"`
+	Rooms   int64  `json:"rooms"`
+	Status  string `json:"status"`
+	Version int64  `json:"version"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleCloseFlat"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Decode the request body into a closeFlatRequest struct
+		var req closeFlatRequest
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("invalid json", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		// Withdraw the flat on behalf of the authenticated owner
+		flat, err := flatService.CloseFlat(r.Context(), flatID, model.FlatStatus(req.Status), h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to close flat", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, flatPkg.ErrNotFlatOwner) ||
+				errors.Is(err, model.ErrTransitionForbidden) {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, model.ErrFlatOnModeration) ||
+				errors.Is(err, model.ErrImpossibleTransition) ||
+				errors.Is(err, flatPkg.ErrFlatModified) {
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the closed flat details
+		log.Info("flat closed succesfully")
+		w.Header().Set("ETag", h.ETag(flat.Version))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, closeFlatResponse{
+			ID:      flat.ID,
+			HouseID: flat.HouseID,
+			Number:  flat.Number,
+			Price:   flat.Price,
+			Rooms:   flat.Rooms,
+			Status:  string(flat.Status),
+			Version: flat.Version,
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/close-flat/mocks"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOwner = model.Actor{
+	ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+	Role: model.Client,
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test owner
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testOwner.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testOwner.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), flatService)
+
+	// Mount handler on router
+	r.Post("/flat/{id}/close", h)
+
+	return r
+}
+
+func TestHandleCloseFlat(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			CloseFlat(gomock.Any(), int64(1), model.StatusSold, testOwner).
+			Return(&model.Flat{ID: 1, HouseID: 10, Price: 100, Rooms: 2, Status: model.StatusSold}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/flat/1/close", bytes.NewReader([]byte(`{"status": "sold"}`)))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response closeFlatResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, closeFlatResponse{
+			ID:      1,
+			HouseID: 10,
+			Price:   100,
+			Rooms:   2,
+			Status:  "sold",
+		}, response)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/flat/1/close", bytes.NewReader([]byte(`{"status": "approved"}`)))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Error, "Validation error")
+	})
+
+	errorCases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"flat not found", flatPkg.ErrFlatNotExist, http.StatusNotFound},
+		{"not owner", flatPkg.ErrNotFlatOwner, http.StatusForbidden},
+		{"transition forbidden", model.ErrTransitionForbidden, http.StatusForbidden},
+		{"flat on moderation", model.ErrFlatOnModeration, http.StatusConflict},
+		{"already sold", model.ErrImpossibleTransition, http.StatusConflict},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				CloseFlat(gomock.Any(), int64(1), model.StatusArchived, testOwner).
+				Return(nil, tc.err)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodPost, "/flat/1/close", bytes.NewReader([]byte(`{"status": "archived"}`)))
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+}
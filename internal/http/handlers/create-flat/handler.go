@@ -19,21 +19,26 @@ import (
 )
 
 type FlatService interface {
-	CreateFlat(ctx context.Context, houseID, price, fooms int64) (*model.Flat, error)
+	CreateFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error)
 }
 
 type createFlatRequest struct {
 	HouseID int64 `json:"house_id" validate:"required,gt=0"`
+	Number  int64 `json:"number" validate:"required,gt=0"`
 	Price   int64 `json:"price" validate:"required,gt=0"`
 	Rooms   int64 `json:"rooms" validate:"required,gt=0"`
+	h.FlatAttributesRequest
 }
 
 type createFlatResponse struct {
 	ID      int64  `json:"id"`
 	HouseID int64  `json:"house_id"`
+	Number  int64  `json:"number"`
 	Price   int64  `json:"price"`
 	Rooms   int64  `json:"rooms"`
 	Status  string `json:"status"`
+	Version int64  `json:"version"`
+	model.FlatAttributes
 }
 
 func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
@@ -64,28 +69,48 @@ func New(log *slog.Logger, validate *validator.Validate, flatService FlatService
 			return
 		}
 
-		// Create the flat
-		flat, err := flatService.CreateFlat(r.Context(), req.HouseID, req.Price, req.Rooms)
+		// Create the flat owned by the authenticated user
+		owner := h.ActorFromContext(r.Context())
+		flat, err := flatService.CreateFlat(r.Context(), model.NewFlat{
+			HouseID: req.HouseID,
+			Number:  req.Number,
+			Price:   req.Price,
+			Rooms:   req.Rooms,
+			OwnerID: owner.ID,
+
+			FlatAttributes: req.FlatAttributesRequest.Model(),
+		})
 		if err != nil {
 			log.Error("failed to create flat", sl.Err(err))
-			if errors.Is(err, flatPkg.ErrHouseNotExist) {
+			if errors.Is(err, flatPkg.ErrHouseNotExist) ||
+				errors.Is(err, model.ErrKitchenAreaTooLarge) {
 				render.Status(r, http.StatusBadRequest)
 				render.JSON(w, r, resp.NewError(err))
 				return
 			}
+			if errors.Is(err, flatPkg.ErrFlatNumberTaken) {
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
 			h.WriteInternalError(r, w, err)
 			return
 		}
 
 		// Return the created flat details
 		log.Info("flat created")
+		w.Header().Set("ETag", h.ETag(flat.Version))
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, createFlatResponse{
 			ID:      flat.ID,
 			HouseID: flat.HouseID,
+			Number:  flat.Number,
 			Price:   flat.Price,
 			Status:  string(flat.Status),
+			Version: flat.Version,
 			Rooms:   flat.Rooms,
+
+			FlatAttributes: flat.FlatAttributes,
 		})
 	}
 }
@@ -14,21 +14,44 @@ import (
 	mwr "avito-backend-bootcamp/internal/http/middleware"
 	"avito-backend-bootcamp/internal/model"
 	flatPkg "avito-backend-bootcamp/internal/service/flat"
-
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
 	resp "avito-backend-bootcamp/pkg/utils/response"
 	"avito-backend-bootcamp/pkg/utils/sl"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var testOwner = model.Actor{
+	ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+	Role: model.Client,
+}
+
+var testNewFlat = model.NewFlat{
+	HouseID: 123,
+	Number:  7,
+	Price:   1000000,
+	Rooms:   2,
+	OwnerID: testOwner.ID,
+}
+
 func setupRouter(flatService FlatService) *chi.Mux {
 	// Create router
 	r := chi.NewRouter()
 
+	// Authenticate requests as the test owner
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testOwner.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testOwner.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
 	// Create handler
 	h := New(sl.SetupLogger(), validator.New(), flatService)
 
@@ -47,11 +70,11 @@ func TestHandleCreateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			CreateFlat(gomock.Any(), int64(123), int64(1000000), int64(2)).
-			Return(&model.Flat{ID: 456, HouseID: 123, Price: 1000000, Rooms: 2, Status: model.StatusCreated}, nil)
+			CreateFlat(gomock.Any(), testNewFlat).
+			Return(&model.Flat{ID: 456, HouseID: 123, Number: 7, Price: 1000000, Rooms: 2, Status: model.StatusCreated}, nil)
 
 		// Create HTTP request
-		reqBody := []byte(`{"house_id": 123, "price": 1000000, "rooms": 2}`)
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -73,12 +96,75 @@ func TestHandleCreateFlat(t *testing.T) {
 		assert.Equal(t, createFlatResponse{
 			ID:      456,
 			HouseID: 123,
+			Number:  7,
 			Price:   1000000,
 			Rooms:   2,
 			Status:  "created",
 		}, response)
 	})
 
+	t.Run("with attributes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		area, kitchen, floor, layout := 42.5, 9.8, int64(3), model.LayoutEuro2
+		attributes := model.FlatAttributes{TotalArea: &area, KitchenArea: &kitchen, Floor: &floor, Layout: &layout}
+
+		newFlat := testNewFlat
+		newFlat.FlatAttributes = attributes
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			CreateFlat(gomock.Any(), newFlat).
+			Return(&model.Flat{ID: 456, HouseID: 123, Number: 7, Price: 1000000, Rooms: 2, Status: model.StatusCreated, FlatAttributes: attributes}, nil)
+
+		// Create HTTP request
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2, ` +
+			`"total_area": 42.5, "kitchen_area": 9.8, "floor": 3, "layout": "euro_2"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response createFlatResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, attributes, response.FlatAttributes)
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2, "layout": "penthouse"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("invalid json", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -116,7 +202,7 @@ func TestHandleCreateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 
 		// Create HTTP request
-		reqBody := []byte(`{"house_id": 0, "price": 1000000, "rooms": 2}`)
+		reqBody := []byte(`{"house_id": 0, "number": 7, "price": 1000000, "rooms": 2}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -146,11 +232,11 @@ func TestHandleCreateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			CreateFlat(gomock.Any(), int64(123), int64(1000000), int64(2)).
+			CreateFlat(gomock.Any(), testNewFlat).
 			Return(nil, flatPkg.ErrHouseNotExist)
 
 		// Create HTTP request
-		reqBody := []byte(`{"house_id": 123, "price": 1000000, "rooms": 2}`)
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -172,6 +258,40 @@ func TestHandleCreateFlat(t *testing.T) {
 		assert.Equal(t, flatPkg.ErrHouseNotExist.Error(), response.Error)
 	})
 
+	t.Run("flat number taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			CreateFlat(gomock.Any(), testNewFlat).
+			Return(nil, flatPkg.ErrFlatNumberTaken)
+
+		// Create HTTP request
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, flatPkg.ErrFlatNumberTaken.Error(), response.Error)
+	})
+
 	t.Run("failed to create flat", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -180,11 +300,11 @@ func TestHandleCreateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			CreateFlat(gomock.Any(), int64(123), int64(1000000), int64(2)).
+			CreateFlat(gomock.Any(), testNewFlat).
 			Return(nil, errors.New("internal error"))
 
 		// Create HTTP request
-		reqBody := []byte(`{"house_id": 123, "price": 1000000, "rooms": 2}`)
+		reqBody := []byte(`{"house_id": 123, "number": 7, "price": 1000000, "rooms": 2}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader(reqBody))
 		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
 
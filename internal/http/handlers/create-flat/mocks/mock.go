@@ -36,16 +36,16 @@ func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
 }
 
 // CreateFlat mocks base method.
-func (m *MockFlatService) CreateFlat(ctx context.Context, houseID, price, fooms int64) (*model.Flat, error) {
+func (m *MockFlatService) CreateFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFlat", ctx, houseID, price, fooms)
+	ret := m.ctrl.Call(m, "CreateFlat", ctx, newFlat)
 	ret0, _ := ret[0].(*model.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateFlat indicates an expected call of CreateFlat.
-func (mr *MockFlatServiceMockRecorder) CreateFlat(ctx, houseID, price, fooms interface{}) *gomock.Call {
+func (mr *MockFlatServiceMockRecorder) CreateFlat(ctx, newFlat interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFlat", reflect.TypeOf((*MockFlatService)(nil).CreateFlat), ctx, houseID, price, fooms)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFlat", reflect.TypeOf((*MockFlatService)(nil).CreateFlat), ctx, newFlat)
 }
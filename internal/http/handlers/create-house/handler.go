@@ -71,7 +71,9 @@ func New(log *slog.Logger, validate *validator.Validate, houseService HouseServi
 		house, err := houseService.CreateHouse(r.Context(), req.Address, req.Developer, req.Year)
 		if err != nil {
 			log.Error("create house failed", sl.Err(err))
-			if errors.Is(err, housePkg.ErrAddressAlreadyUsed) {
+			if errors.Is(err, housePkg.ErrAddressAlreadyUsed) ||
+				errors.Is(err, housePkg.ErrHouseNearDuplicate) ||
+				errors.Is(err, model.ErrAddressNotFound) {
 				render.Status(r, http.StatusBadRequest)
 				render.JSON(w, r, resp.NewError(err))
 				return
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -46,7 +47,7 @@ func TestHandleCreateHouse(t *testing.T) {
 
 		// Setup mock house service
 		houseService := mock.NewMockHouseService(ctrl)
-		now := time.Now().Truncate(time.Second)
+		now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
 		houseService.
 			EXPECT().
 			CreateHouse(gomock.Any(), "some address", "some developer", int64(2023)).
@@ -182,6 +183,41 @@ func TestHandleCreateHouse(t *testing.T) {
 		assert.Equal(t, housePkg.ErrAddressAlreadyUsed.Error(), response.Error)
 	})
 
+	t.Run("near duplicate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		duplicateErr := fmt.Errorf("%w: house 7 at other address", housePkg.ErrHouseNearDuplicate)
+		houseService.
+			EXPECT().
+			CreateHouse(gomock.Any(), "some address", "some developer", int64(2023)).
+			Return(nil, duplicateErr)
+
+		// Create HTTP request
+		reqBody := []byte(`{"address": "some address", "year": 2023, "developer": "some developer"}`)
+		req := httptest.NewRequest(http.MethodPost, "/house/create", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(houseService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, duplicateErr.Error(), response.Error)
+	})
+
 	t.Run("failed to create house", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	DeleteFlatPhoto(ctx context.Context, flatID int64, photoID int64, actor model.Actor) error
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleDeleteFlatPhoto"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat and photo IDs from URL parameters
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		photoID, err := strconv.ParseInt(chi.URLParam(r, "photoID"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Delete the photo on behalf of the authenticated owner
+		err = flatService.DeleteFlatPhoto(r.Context(), flatID, photoID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to delete photo", sl.Err(err))
+			switch {
+			case errors.Is(err, flatPkg.ErrFlatNotExist),
+				errors.Is(err, flatPkg.ErrFlatPhotoNotExist):
+				render.Status(r, http.StatusNotFound)
+			case errors.Is(err, flatPkg.ErrNotFlatOwner):
+				render.Status(r, http.StatusForbidden)
+			case errors.Is(err, model.ErrFlatOnModeration),
+				errors.Is(err, model.ErrFlatClosed):
+				render.Status(r, http.StatusConflict)
+			default:
+				h.WriteInternalError(r, w, err)
+				return
+			}
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Confirm the deletion
+		log.Info("photo deleted succesfully")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/delete-flat-photo/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOwner = model.Actor{
+	ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+	Role: model.Client,
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test owner
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testOwner.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testOwner.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Delete("/flat/{id}/photos/{photoID}", h)
+
+	return r
+}
+
+func TestHandleDeleteFlatPhoto(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			DeleteFlatPhoto(gomock.Any(), int64(1), int64(5), testOwner).
+			Return(nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodDelete, "/flat/1/photos/5", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+	})
+
+	t.Run("invalid photo id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodDelete, "/flat/1/photos/abc", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	errorCases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"flat not found", flatPkg.ErrFlatNotExist, http.StatusNotFound},
+		{"photo not found", flatPkg.ErrFlatPhotoNotExist, http.StatusNotFound},
+		{"foreign flat", flatPkg.ErrNotFlatOwner, http.StatusForbidden},
+		{"flat on moderation", model.ErrFlatOnModeration, http.StatusConflict},
+		{"flat closed", model.ErrFlatClosed, http.StatusConflict},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				DeleteFlatPhoto(gomock.Any(), int64(1), int64(5), testOwner).
+				Return(tc.err)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodDelete, "/flat/1/photos/5", nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+
+	t.Run("failed to delete photo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			DeleteFlatPhoto(gomock.Any(), int64(1), int64(5), testOwner).
+			Return(errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodDelete, "/flat/1/photos/5", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal", response.Message)
+	})
+}
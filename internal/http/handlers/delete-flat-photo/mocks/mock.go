@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/http/handlers/delete-flat-photo/handler.go
+
+// Package mock_handlers is a generated GoMock package.
+package mock_handlers
+
+import (
+	model "avito-backend-bootcamp/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFlatService is a mock of FlatService interface.
+type MockFlatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatServiceMockRecorder
+}
+
+// MockFlatServiceMockRecorder is the mock recorder for MockFlatService.
+type MockFlatServiceMockRecorder struct {
+	mock *MockFlatService
+}
+
+// NewMockFlatService creates a new mock instance.
+func NewMockFlatService(ctrl *gomock.Controller) *MockFlatService {
+	mock := &MockFlatService{ctrl: ctrl}
+	mock.recorder = &MockFlatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteFlatPhoto mocks base method.
+func (m *MockFlatService) DeleteFlatPhoto(ctx context.Context, flatID, photoID int64, actor model.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFlatPhoto", ctx, flatID, photoID, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFlatPhoto indicates an expected call of DeleteFlatPhoto.
+func (mr *MockFlatServiceMockRecorder) DeleteFlatPhoto(ctx, flatID, photoID, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFlatPhoto", reflect.TypeOf((*MockFlatService)(nil).DeleteFlatPhoto), ctx, flatID, photoID, actor)
+}
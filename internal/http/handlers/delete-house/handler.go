@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	housePkg "avito-backend-bootcamp/internal/service/house"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type HouseService interface {
+	DeleteHouse(ctx context.Context, ID int64) error
+}
+
+func New(log *slog.Logger, houseService HouseService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleDeleteHouse"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract house ID from URL parameter
+		houseID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Delete the house along with its flats and subscriptions
+		err = houseService.DeleteHouse(r.Context(), houseID)
+		if err != nil {
+			log.Error("failed to delete house", sl.Err(err))
+			if errors.Is(err, housePkg.ErrHouseNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Confirm the deletion
+		log.Info("house deleted")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
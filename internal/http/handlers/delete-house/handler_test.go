@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/delete-house/mocks"
+	housePkg "avito-backend-bootcamp/internal/service/house"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(houseService HouseService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), houseService)
+
+	// Mount handler on router
+	r.Delete("/house/{id}", h)
+
+	return r
+}
+
+func TestHandleDeleteHouse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			DeleteHouse(gomock.Any(), int64(1)).
+			Return(nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodDelete, "/house/1", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			DeleteHouse(gomock.Any(), int64(1)).
+			Return(housePkg.ErrHouseNotExist)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodDelete, "/house/1", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, housePkg.ErrHouseNotExist.Error(), response.Error)
+	})
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/http/handlers/delete-house/handler.go
+
+// Package mock_handlers is a generated GoMock package.
+package mock_handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHouseService is a mock of HouseService interface.
+type MockHouseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHouseServiceMockRecorder
+}
+
+// MockHouseServiceMockRecorder is the mock recorder for MockHouseService.
+type MockHouseServiceMockRecorder struct {
+	mock *MockHouseService
+}
+
+// NewMockHouseService creates a new mock instance.
+func NewMockHouseService(ctrl *gomock.Controller) *MockHouseService {
+	mock := &MockHouseService{ctrl: ctrl}
+	mock.recorder = &MockHouseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHouseService) EXPECT() *MockHouseServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteHouse mocks base method.
+func (m *MockHouseService) DeleteHouse(ctx context.Context, ID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouse", ctx, ID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouse indicates an expected call of DeleteHouse.
+func (mr *MockHouseServiceMockRecorder) DeleteHouse(ctx, ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouse", reflect.TypeOf((*MockHouseService)(nil).DeleteHouse), ctx, ID)
+}
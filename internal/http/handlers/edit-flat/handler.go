@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	EditFlat(ctx context.Context, ID int64, edit model.FlatEdit, actor model.Actor) (*model.Flat, error)
+}
+
+type editFlatRequest struct {
+	Price *int64 `json:"price" validate:"omitempty,gt=0"`
+	Rooms *int64 `json:"rooms" validate:"omitempty,gt=0"`
+	h.FlatAttributesRequest
+}
+
+type editFlatResponse struct {
+	ID      int64  `json:"id"`
+	HouseID int64  `json:"house_id"`
+	Number  int64  `json:"number"`
+	Price   int64  `json:"price"`
+	Rooms   int64  `json:"rooms"`
+	Status  string `json:"status"`
+	Version int64  `json:"version"`
+	model.FlatAttributes
+}
+
+var errEmptyEdit = errors.New("nothing to edit: at least one flat parameter must be set")
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleEditFlat"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Decode the request body into an editFlatRequest struct
+		var req editFlatRequest
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("invalid json", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+		if req.Price == nil && req.Rooms == nil && req.FlatAttributesRequest.Empty() {
+			log.Error("empty edit request")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(errEmptyEdit))
+			return
+		}
+
+		// Edit the flat on behalf of the authenticated owner
+		edit := model.FlatEdit{
+			Price:          req.Price,
+			Rooms:          req.Rooms,
+			FlatAttributes: req.FlatAttributesRequest.Model(),
+		}
+		flat, err := flatService.EditFlat(r.Context(), flatID, edit, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to edit flat", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, model.ErrKitchenAreaTooLarge) {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, flatPkg.ErrNotFlatOwner) ||
+				errors.Is(err, model.ErrTransitionForbidden) {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, model.ErrFlatOnModeration) ||
+				errors.Is(err, model.ErrFlatClosed) ||
+				errors.Is(err, flatPkg.ErrFlatModified) {
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the edited flat details
+		log.Info("flat edited succesfully")
+		w.Header().Set("ETag", h.ETag(flat.Version))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, editFlatResponse{
+			ID:      flat.ID,
+			HouseID: flat.HouseID,
+			Number:  flat.Number,
+			Price:   flat.Price,
+			Rooms:   flat.Rooms,
+			Status:  string(flat.Status),
+			Version: flat.Version,
+
+			FlatAttributes: flat.FlatAttributes,
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	GetFlatRuleDecisions(ctx context.Context, flatID int64, actor model.Actor, filter model.RuleDecisionFilter) (*model.RuleDecisionPage, error)
+}
+
+type flatDecisionsRequest struct {
+	Limit  int64 `validate:"omitempty,gte=1,lte=100"`
+	Cursor string
+}
+
+type flatDecisionsResponse struct {
+	Decisions  []*model.RuleDecision `json:"decisions"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatDecisions"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Parse pagination params
+		var req flatDecisionsRequest
+		req.Limit, err = query.Int64(r.URL.Query(), "limit")
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.Cursor = r.URL.Query().Get("cursor")
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		filter := model.RuleDecisionFilter{Limit: req.Limit}
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
+		// Retrieve the premoderation decisions on the flat on behalf of the authenticated user
+		page, err := flatService.GetFlatRuleDecisions(r.Context(), flatID, h.ActorFromContext(r.Context()), filter)
+		if err != nil {
+			log.Error("failed to get flat decisions", sl.Err(err))
+			switch {
+			case errors.Is(err, flatPkg.ErrFlatNotExist):
+				render.Status(r, http.StatusNotFound)
+			case errors.Is(err, flatPkg.ErrHistoryForbidden):
+				render.Status(r, http.StatusForbidden)
+			default:
+				h.WriteInternalError(r, w, err)
+				return
+			}
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Return the flat decisions
+		log.Info("successfully get flat decisions")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, flatDecisionsResponse{
+			Decisions:  page.Decisions,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/flat-decisions/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testClient = model.Actor{
+		ID:   uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"),
+		Role: model.Client,
+	}
+	testModerator = model.Actor{
+		ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+		Role: model.Moderator,
+	}
+)
+
+func setupRouter(flatService FlatService, actor model.Actor) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the given user
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, actor.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), flatService)
+
+	// Mount handler on router
+	r.Get("/flat/{id}/decisions", h)
+
+	return r
+}
+
+func TestHandleFlatDecisions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		rule := "banned_words"
+		decisions := []*model.RuleDecision{
+			{
+				ID:        4,
+				FlatID:    1,
+				Rule:      &rule,
+				Action:    model.RuleActionFlag,
+				CreatedAt: time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC),
+			},
+		}
+		next := model.Cursor{Value: 4, ID: 4}.Encode()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatRuleDecisions(gomock.Any(), int64(1), testModerator, model.RuleDecisionFilter{
+				Limit: 1,
+				After: &model.Cursor{Value: 3, ID: 3},
+			}).
+			Return(&model.RuleDecisionPage{Decisions: decisions, NextCursor: next}, nil)
+
+		// Create HTTP request
+		cursor := model.Cursor{Value: 3, ID: 3}.Encode()
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/decisions?limit=1&cursor="+cursor, nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response flatDecisionsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, flatDecisionsResponse{Decisions: decisions, NextCursor: next}, response)
+	})
+
+	t.Run("default page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatRuleDecisions(gomock.Any(), int64(1), testModerator, model.RuleDecisionFilter{}).
+			Return(&model.RuleDecisionPage{Decisions: []*model.RuleDecision{}}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/decisions", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"decisions":[]}`, w.Body.String())
+	})
+
+	invalidCases := []struct {
+		name string
+		url  string
+	}{
+		{"invalid id", "/flat/abc/decisions"},
+		{"limit not a number", "/flat/1/decisions?limit=abc"},
+		{"limit too large", "/flat/1/decisions?limit=101"},
+		{"invalid cursor", "/flat/1/decisions?cursor=%21%21"},
+	}
+
+	for _, tc := range invalidCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	errorCases := []struct {
+		name  string
+		actor model.Actor
+		err   error
+		code  int
+	}{
+		{"flat not found", testModerator, flatPkg.ErrFlatNotExist, http.StatusNotFound},
+		{"client forbidden", testClient, flatPkg.ErrHistoryForbidden, http.StatusForbidden},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				GetFlatRuleDecisions(gomock.Any(), int64(1), tc.actor, model.RuleDecisionFilter{}).
+				Return(nil, tc.err)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, "/flat/1/decisions", nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService, tc.actor).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+
+	t.Run("failed to get decisions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatRuleDecisions(gomock.Any(), int64(1), testModerator, model.RuleDecisionFilter{}).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/decisions", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal", response.Message)
+	})
+}
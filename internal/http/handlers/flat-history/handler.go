@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetFlatHistory(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatStatusChange, error)
+}
+
+type flatHistoryResponse struct {
+	History []*model.FlatStatusChange `json:"history"`
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatHistory"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Retrieve the status history of the flat on behalf of the authenticated user
+		history, err := flatService.GetFlatHistory(r.Context(), flatID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to get flat history", sl.Err(err))
+			switch {
+			case errors.Is(err, flatPkg.ErrFlatNotExist):
+				render.Status(r, http.StatusNotFound)
+			case errors.Is(err, flatPkg.ErrHistoryForbidden):
+				render.Status(r, http.StatusForbidden)
+			default:
+				h.WriteInternalError(r, w, err)
+				return
+			}
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Return the flat history
+		log.Info("successfully get flat history")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, flatHistoryResponse{
+			History: history,
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/flat-history/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testClient = model.Actor{
+		ID:   uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"),
+		Role: model.Client,
+	}
+	testModerator = model.Actor{
+		ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+		Role: model.Moderator,
+	}
+)
+
+func setupRouter(flatService FlatService, actor model.Actor) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the given user
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, actor.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Get("/flat/{id}/history", h)
+
+	return r
+}
+
+func TestHandleFlatHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		history := []*model.FlatStatusChange{
+			{
+				ID:        1,
+				FlatID:    1,
+				OldStatus: model.StatusCreated,
+				NewStatus: model.StatusOnModeration,
+				ActorID:   testModerator.ID,
+				ActorRole: model.Moderator,
+				CreatedAt: time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC),
+			},
+		}
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatHistory(gomock.Any(), int64(1), testModerator).
+			Return(history, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/history", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response flatHistoryResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, history, response.History)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/abc/history", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	errorCases := []struct {
+		name  string
+		actor model.Actor
+		err   error
+		code  int
+	}{
+		{"flat not found", testModerator, flatPkg.ErrFlatNotExist, http.StatusNotFound},
+		{"client forbidden", testClient, flatPkg.ErrHistoryForbidden, http.StatusForbidden},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				GetFlatHistory(gomock.Any(), int64(1), tc.actor).
+				Return(nil, tc.err)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, "/flat/1/history", nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService, tc.actor).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+
+	t.Run("failed to get history", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatHistory(gomock.Any(), int64(1), testModerator).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/history", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testModerator).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal", response.Message)
+	})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetFlat(ctx context.Context, ID int64, actor model.Actor) (*model.Flat, error)
+}
+
+// HandleFlatPhotoFile отдает файлы фотографий квартиры только тем, кому видна квартира.
+// Файлы отдаются обработчиком files
+func New(log *slog.Logger, flatService FlatService, files http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatPhotoFile"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Make sure the flat is visible to the authenticated user
+		_, err = flatService.GetFlat(r.Context(), flatID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to get flat", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Serve the photo file
+		files.ServeHTTP(w, r)
+	}
+}
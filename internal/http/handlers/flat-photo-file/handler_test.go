@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/flat-photo-file/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testClient = model.Actor{
+	ID:   uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"),
+	Role: model.Client,
+}
+
+// testFiles serves the requested path back instead of a file.
+var testFiles = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(r.URL.Path))
+})
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test client
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testClient.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testClient.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService, http.StripPrefix("/media", testFiles))
+
+	// Mount handler on router
+	r.Get("/media/flats/{id}/*", h)
+
+	return r
+}
+
+func TestHandleFlatPhotoFile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1), testClient).
+			Return(&model.Flat{ID: 1, Status: model.StatusApproved}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/media/flats/1/photo.jpg", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/flats/1/photo.jpg", w.Body.String())
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/media/flats/abc/photo.jpg", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("flat not visible", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1), testClient).
+			Return(nil, flatPkg.ErrFlatNotExist)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/media/flats/1/photo.jpg", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, resp.NewError(flatPkg.ErrFlatNotExist), response)
+	})
+
+	t.Run("failed to get flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1), testClient).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/media/flats/1/photo.jpg", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal", response.Message)
+	})
+}
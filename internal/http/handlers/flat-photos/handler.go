@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetFlatPhotos(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatPhoto, error)
+}
+
+type flatPhotosResponse struct {
+	Photos []*model.FlatPhoto `json:"photos"`
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatPhotos"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Retrieve the photos of the flat
+		photos, err := flatService.GetFlatPhotos(r.Context(), flatID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to get flat photos", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the flat photos
+		log.Info("successfully get flat photos")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, flatPhotosResponse{
+			Photos: photos,
+		})
+	}
+}
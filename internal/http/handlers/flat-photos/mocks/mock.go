@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/http/handlers/flat-photos/handler.go
+
+// Package mock_handlers is a generated GoMock package.
+package mock_handlers
+
+import (
+	model "avito-backend-bootcamp/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFlatService is a mock of FlatService interface.
+type MockFlatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatServiceMockRecorder
+}
+
+// MockFlatServiceMockRecorder is the mock recorder for MockFlatService.
+type MockFlatServiceMockRecorder struct {
+	mock *MockFlatService
+}
+
+// NewMockFlatService creates a new mock instance.
+func NewMockFlatService(ctrl *gomock.Controller) *MockFlatService {
+	mock := &MockFlatService{ctrl: ctrl}
+	mock.recorder = &MockFlatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFlatPhotos mocks base method.
+func (m *MockFlatService) GetFlatPhotos(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFlatPhotos", ctx, flatID, actor)
+	ret0, _ := ret[0].([]*model.FlatPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlatPhotos indicates an expected call of GetFlatPhotos.
+func (mr *MockFlatServiceMockRecorder) GetFlatPhotos(ctx, flatID, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatPhotos", reflect.TypeOf((*MockFlatService)(nil).GetFlatPhotos), ctx, flatID, actor)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetFlatPriceHistory(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatPriceChange, error)
+}
+
+type flatPricesResponse struct {
+	Prices []*model.FlatPriceChange `json:"prices"`
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatPrices"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Retrieve the price history of the flat
+		prices, err := flatService.GetFlatPriceHistory(r.Context(), flatID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to get flat price history", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the flat price history
+		log.Info("successfully get flat price history")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, flatPricesResponse{
+			Prices: prices,
+		})
+	}
+}
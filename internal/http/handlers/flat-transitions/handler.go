@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetFlatTransitions(ctx context.Context, flatID int64, actor model.Actor) ([]model.FlatTransition, error)
+}
+
+type flatTransitionsResponse struct {
+	Transitions []model.FlatTransition `json:"transitions"`
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleFlatTransitions"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Retrieve the transitions available to the authenticated user
+		transitions, err := flatService.GetFlatTransitions(r.Context(), flatID, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to get flat transitions", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrFlatNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the flat transitions
+		log.Info("successfully get flat transitions")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, flatTransitionsResponse{
+			Transitions: transitions,
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/flat-transitions/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testOwner = model.Actor{
+		ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+		Role: model.Client,
+	}
+	testModerator = model.Actor{
+		ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+		Role: model.Moderator,
+	}
+)
+
+func setupRouter(flatService FlatService, actor model.Actor) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the given user
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, actor.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Get("/flat/{id}/transitions", h)
+
+	return r
+}
+
+func TestHandleFlatTransitions(t *testing.T) {
+	successCases := []struct {
+		name        string
+		actor       model.Actor
+		transitions []model.FlatTransition
+		expected    string
+	}{
+		{
+			name:  "owner",
+			actor: testOwner,
+			transitions: []model.FlatTransition{
+				{From: model.StatusDeclined, To: model.StatusCreated, Roles: []model.UserType{model.Client}, Guard: model.GuardOwner},
+				{From: model.StatusDeclined, To: model.StatusArchived, Roles: []model.UserType{model.Client}, Guard: model.GuardOwner},
+			},
+			expected: `{"transitions":[{"from":"declined","to":"created"},{"from":"declined","to":"archived"}]}`,
+		},
+		{
+			name:  "moderator",
+			actor: testModerator,
+			transitions: []model.FlatTransition{
+				{From: model.StatusApproved, To: model.StatusOnModeration, Roles: []model.UserType{model.Moderator}, InvalidateCache: true},
+			},
+			expected: `{"transitions":[{"from":"approved","to":"on_moderation"}]}`,
+		},
+		{
+			name:        "nothing available",
+			actor:       testOwner,
+			transitions: []model.FlatTransition{},
+			expected:    `{"transitions":[]}`,
+		},
+	}
+
+	for _, tc := range successCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				GetFlatTransitions(gomock.Any(), int64(1), tc.actor).
+				Return(tc.transitions, nil)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, "/flat/1/transitions", nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService, tc.actor).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tc.expected, w.Body.String())
+		})
+	}
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/abc/transitions", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testOwner).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("flat not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatTransitions(gomock.Any(), int64(1), testOwner).
+			Return(nil, flatPkg.ErrFlatNotExist)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/transitions", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testOwner).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, resp.NewError(flatPkg.ErrFlatNotExist), response)
+	})
+
+	t.Run("failed to get transitions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatTransitions(gomock.Any(), int64(1), testOwner).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1/transitions", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testOwner).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal", response.Message)
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/query"
+	"net/url"
+)
+
+// FlatAttributesRequest holds the optional flat attributes accepted on create and edit.
+type FlatAttributesRequest struct {
+	TotalArea   *float64 `json:"total_area" validate:"omitempty,gt=0,lte=10000"`
+	KitchenArea *float64 `json:"kitchen_area" validate:"omitempty,gt=0,lte=10000"`
+	Floor       *int64   `json:"floor" validate:"omitempty,gt=0,lte=200"`
+	Description *string  `json:"description" validate:"omitempty,max=5000"`
+	Layout      *string  `json:"layout" validate:"omitempty,oneof=studio euro_2 euro_3 euro_4 classic open_plan"`
+}
+
+// Empty reports whether none of the attributes is set.
+func (req FlatAttributesRequest) Empty() bool {
+	return req.TotalArea == nil && req.KitchenArea == nil && req.Floor == nil &&
+		req.Description == nil && req.Layout == nil
+}
+
+// Model converts the validated request into flat attributes.
+func (req FlatAttributesRequest) Model() model.FlatAttributes {
+	attributes := model.FlatAttributes{
+		TotalArea:   req.TotalArea,
+		KitchenArea: req.KitchenArea,
+		Floor:       req.Floor,
+		Description: req.Description,
+	}
+	if req.Layout != nil {
+		layout := model.FlatLayout(*req.Layout)
+		attributes.Layout = &layout
+	}
+	return attributes
+}
+
+// FlatAttributesQuery holds the attribute filters shared by the flat listings.
+type FlatAttributesQuery struct {
+	AreaMin  float64  `validate:"gte=0"`
+	AreaMax  float64  `validate:"omitempty,gtefield=AreaMin"`
+	FloorMin int64    `validate:"gte=0"`
+	FloorMax int64    `validate:"omitempty,gtefield=FloorMin"`
+	Layouts  []string `validate:"dive,oneof=studio euro_2 euro_3 euro_4 classic open_plan"`
+}
+
+// ParseFlatAttributesQuery parses the attribute filters from the query params.
+func ParseFlatAttributesQuery(values url.Values) (q FlatAttributesQuery, err error) {
+	if q.AreaMin, err = query.Float64(values, "area_from"); err != nil {
+		return q, err
+	}
+	if q.AreaMax, err = query.Float64(values, "area_to"); err != nil {
+		return q, err
+	}
+	if q.FloorMin, err = query.Int64(values, "floor_from"); err != nil {
+		return q, err
+	}
+	if q.FloorMax, err = query.Int64(values, "floor_to"); err != nil {
+		return q, err
+	}
+	q.Layouts = query.StringList(values, "layout")
+
+	return q, nil
+}
+
+// Apply adds the validated attribute filters to the flat filter.
+func (q FlatAttributesQuery) Apply(filter *model.FlatFilter) {
+	filter.AreaMin = q.AreaMin
+	filter.AreaMax = q.AreaMax
+	filter.FloorMin = q.FloorMin
+	filter.FloorMax = q.FloorMax
+	for _, layout := range q.Layouts {
+		filter.Layouts = append(filter.Layouts, model.FlatLayout(layout))
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/get-flat/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testOwner = model.Actor{
+		ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+		Role: model.Client,
+	}
+	testClient = model.Actor{
+		ID:   uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"),
+		Role: model.Client,
+	}
+	testModerator = model.Actor{
+		ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+		Role: model.Moderator,
+	}
+)
+
+func setupRouter(flatService FlatService, actor model.Actor) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the given user
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, actor.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Get("/flat/{id}", h)
+
+	return r
+}
+
+// newTestFlat returns an approved flat of the test owner flagged by premoderation.
+func newTestFlat() *model.Flat {
+	return &model.Flat{
+		ID:      1,
+		HouseID: 10,
+		Number:  7,
+		Price:   100000,
+		Rooms:   2,
+		Status:  model.StatusApproved,
+		Flagged: true,
+		OwnerID: &testOwner.ID,
+		Version: 3,
+	}
+}
+
+func TestHandleGetFlat(t *testing.T) {
+	visibilityCases := []struct {
+		name     string
+		actor    model.Actor
+		expected func(flat *model.Flat)
+	}{
+		{
+			name:  "owner sees owner but not flag",
+			actor: testOwner,
+			expected: func(flat *model.Flat) {
+				flat.Flagged = false
+			},
+		},
+		{
+			name:     "moderator sees owner and flag",
+			actor:    testModerator,
+			expected: func(flat *model.Flat) {},
+		},
+		{
+			name:  "other client sees neither",
+			actor: testClient,
+			expected: func(flat *model.Flat) {
+				flat.OwnerID = nil
+				flat.Flagged = false
+			},
+		},
+	}
+
+	for _, tc := range visibilityCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				GetFlat(gomock.Any(), int64(1), tc.actor).
+				Return(newTestFlat(), nil)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, "/flat/1", nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService, tc.actor).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+			var response model.Flat
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			expected := newTestFlat()
+			tc.expected(expected)
+			assert.Equal(t, *expected, response)
+		})
+	}
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/abc", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testClient).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("flat not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1), testClient).
+			Return(nil, flatPkg.ErrFlatNotExist)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testClient).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, resp.NewError(flatPkg.ErrFlatNotExist), response)
+	})
+
+	t.Run("failed to get flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1), testClient).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/1", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService, testClient).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, handlers.ErrorResponse{
+			Message:   "internal",
+			RequestID: "test",
+			Code:      http.StatusInternalServerError,
+		}, response)
+	})
+}
@@ -3,10 +3,15 @@ package handlers
 import (
 	h "avito-backend-bootcamp/internal/http/handlers"
 	"avito-backend-bootcamp/internal/model"
-	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	housePkg "avito-backend-bootcamp/internal/service/house"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	"avito-backend-bootcamp/pkg/utils/query"
 	resp "avito-backend-bootcamp/pkg/utils/response"
 	"avito-backend-bootcamp/pkg/utils/sl"
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"log/slog"
 	"net/http"
@@ -14,17 +19,41 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
 )
 
+type HouseService interface {
+	GetHouse(ctx context.Context, ID int64) (*model.HouseSummary, error)
+}
+
 type FlatService interface {
-	GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType) ([]*model.Flat, error)
+	GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType, filter model.FlatFilter) (*model.FlatPage, error)
+}
+
+type getHouseRequest struct {
+	Sort   string `validate:"omitempty,oneof=id_asc id_desc price_asc price_desc rooms_asc rooms_desc"`
+	Limit  int64  `validate:"omitempty,gte=1,lte=100"`
+	Cursor string
+	h.FlatAttributesQuery
 }
 
 type getHouseResponse struct {
-	Flats []*model.Flat
+	ID                  int64      `json:"id"`
+	Address             string     `json:"address"`
+	Year                int64      `json:"year"`
+	Developer           *string    `json:"developer,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	LastFlatPublishedAt *time.Time `json:"last_flat_published_at,omitempty"`
+	ApprovedFlats       int64      `json:"approved_flats"`
+	Subscribers         int64      `json:"subscribers"`
+
+	Flats      []*model.Flat
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
-func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+func New(log *slog.Logger, validate *validator.Validate, houseService HouseService, flatService FlatService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Setup logger
 		const op = "handlers.HandleGetHouse"
@@ -42,23 +71,95 @@ func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
 			return
 		}
 
+		// Parse pagination params
+		var req getHouseRequest
+		req.Limit, err = query.Int64(r.URL.Query(), "limit")
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.FlatAttributesQuery, err = h.ParseFlatAttributesQuery(r.URL.Query())
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.Sort = r.URL.Query().Get("sort")
+		req.Cursor = r.URL.Query().Get("cursor")
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		filter := model.FlatFilter{
+			Sort:  model.FlatSort(req.Sort),
+			Limit: req.Limit,
+		}
+		req.FlatAttributesQuery.Apply(&filter)
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
 		// Extract audience from the context
-		userType := r.Context().Value(pkgCtx.KeyUserType).(model.UserType)
+		actor := h.ActorFromContext(r.Context())
 		log.Info("audience extracted from request")
 
-		// Retrieve flats associated with the house ID
-		flatList, err := flatService.GetFlatListByHouseID(r.Context(), houseID, userType)
+		// Retrieve the house details
+		house, err := houseService.GetHouse(r.Context(), houseID)
+		if err != nil {
+			log.Error("failed to get house", sl.Err(err))
+			if errors.Is(err, housePkg.ErrHouseNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Retrieve a page of flats associated with the house ID
+		page, err := flatService.GetFlatListByHouseID(r.Context(), houseID, actor.Role, filter)
 		if err != nil {
 			log.Error("failed to get list of flats for house", sl.Err(err))
 			h.WriteInternalError(r, w, err)
 			return
 		}
 
-		// Return the list of flats
-		log.Info("successfully get list of flats")
+		// Return the house details with the list of flats without the fields hidden from the user
+		for _, flat := range page.Flats {
+			flat.HideFrom(actor)
+		}
+		log.Info("successfully get house with list of flats")
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, getHouseResponse{
-			Flats: flatList,
+			ID:                  house.ID,
+			Address:             house.Address,
+			Year:                house.YearOfConstruction,
+			Developer:           dbUtil.FromNullString(house.Developer),
+			CreatedAt:           house.CreatedAt,
+			UpdatedAt:           house.UpdatedAt,
+			LastFlatPublishedAt: house.LastFlatPublishedAt,
+			ApprovedFlats:       house.ApprovedFlats,
+			Subscribers:         house.Subscribers,
+
+			Flats:      page.Flats,
+			Total:      page.Total,
+			NextCursor: page.NextCursor,
 		})
 	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
 	resp "avito-backend-bootcamp/pkg/utils/response"
 	"context"
 	"encoding/json"
@@ -9,26 +10,64 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"avito-backend-bootcamp/internal/http/handlers"
 	mock "avito-backend-bootcamp/internal/http/handlers/get-house/mocks"
 	mwr "avito-backend-bootcamp/internal/http/middleware"
 	"avito-backend-bootcamp/internal/model"
+	housePkg "avito-backend-bootcamp/internal/service/house"
 
 	"avito-backend-bootcamp/pkg/utils/sl"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func setupRouter(flatService FlatService) *chi.Mux {
+var testHouse = &model.HouseSummary{
+	House: model.House{
+		ID:                 123,
+		Address:            "some address",
+		YearOfConstruction: 2023,
+		Developer:          dbUtil.NewNullString("some developer"),
+		CreatedAt:          time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:          time.Date(2024, time.May, 2, 12, 0, 0, 0, time.UTC),
+	},
+	ApprovedFlats: 4,
+	Subscribers:   2,
+}
+
+func setupHouseService(ctrl *gomock.Controller) *mock.MockHouseService {
+	houseService := mock.NewMockHouseService(ctrl)
+	houseService.
+		EXPECT().
+		GetHouse(gomock.Any(), int64(123)).
+		Return(testHouse, nil)
+	return houseService
+}
+
+var (
+	testClient    = model.Actor{ID: uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"), Role: model.Client}
+	testModerator = model.Actor{ID: uuid.MustParse("8e4a1f06-7b2c-4d9e-a3f5-6c0b9d2e1a74"), Role: model.Moderator}
+)
+
+// withActor puts the authenticated user into the request context.
+func withActor(req *http.Request, actor model.Actor) *http.Request {
+	ctx := context.WithValue(req.Context(), pkgCtx.KeyUserID, actor.ID)
+	ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+	return req.WithContext(ctx)
+}
+
+func setupRouter(houseService HouseService, flatService FlatService) *chi.Mux {
 	// Create router
 	r := chi.NewRouter()
 
 	// Create handler
-	h := New(sl.SetupLogger(), flatService)
+	h := New(sl.SetupLogger(), validator.New(), houseService, flatService)
 
 	// Mount handler on router
 	r.Get("/house/{id}", h)
@@ -41,22 +80,77 @@ func TestHandleGetHouse(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		// Setup mock flat service
+		// Setup mock house and flat services
+		houseService := setupHouseService(ctrl)
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			GetFlatListByHouseID(gomock.Any(), int64(123), model.Moderator).
-			Return([]*model.Flat{{ID: 1}}, nil)
+			GetFlatListByHouseID(gomock.Any(), int64(123), model.Moderator, model.FlatFilter{}).
+			Return(&model.FlatPage{Flats: []*model.Flat{{ID: 1}}, Total: 1}, nil)
 
 		// Create HTTP request
 		req := httptest.NewRequest(http.MethodGet, "/house/123", nil)
-		req = req.WithContext(context.WithValue(req.Context(), pkgCtx.KeyUserType, model.Moderator))
+		req = withActor(req, testModerator)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(houseService, flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response getHouseResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		developer := "some developer"
+		assert.Equal(t, getHouseResponse{
+			ID:            123,
+			Address:       "some address",
+			Year:          2023,
+			Developer:     &developer,
+			CreatedAt:     testHouse.CreatedAt,
+			UpdatedAt:     testHouse.UpdatedAt,
+			ApprovedFlats: 4,
+			Subscribers:   2,
+			Flats:         []*model.Flat{{ID: 1}},
+			Total:         1,
+		}, response)
+	})
+
+	t.Run("success with pagination", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cursor := model.Cursor{Value: 3, ID: 10}
+		ownerID := uuid.New()
+
+		// Setup mock house and flat services
+		houseService := setupHouseService(ctrl)
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetFlatListByHouseID(gomock.Any(), int64(123), model.Client, model.FlatFilter{
+				Sort:  model.SortRoomsDesc,
+				Limit: 1,
+				After: &cursor,
+			}).
+			Return(&model.FlatPage{Flats: []*model.Flat{{ID: 11, OwnerID: &ownerID, Flagged: true}}, Total: 5, NextCursor: "next"}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123?sort=rooms_desc&limit=1&cursor="+cursor.Encode(), nil)
+		req = withActor(req, testClient)
 
 		// Create HTTP response writer
 		w := httptest.NewRecorder()
 
 		// Create router
-		r := setupRouter(flatService)
+		r := setupRouter(houseService, flatService)
 
 		// Execute handler
 		r.ServeHTTP(w, req)
@@ -68,7 +162,33 @@ func TestHandleGetHouse(t *testing.T) {
 		var response getHouseResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, []*model.Flat{{ID: 1}}, response.Flats)
+		assert.Equal(t, []*model.Flat{{ID: 11}}, response.Flats)
+		assert.Equal(t, int64(5), response.Total)
+		assert.Equal(t, "next", response.NextCursor)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123?sort=address", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(mock.NewMockHouseService(ctrl), flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("invalid house ID", func(t *testing.T) {
@@ -80,13 +200,13 @@ func TestHandleGetHouse(t *testing.T) {
 
 		// Create HTTP request
 		req := httptest.NewRequest(http.MethodGet, "/house/abc", nil)
-		req = req.WithContext(context.WithValue(req.Context(), pkgCtx.KeyUserType, model.Moderator))
+		req = withActor(req, testModerator)
 
 		// Create HTTP response writer
 		w := httptest.NewRecorder()
 
 		// Create router
-		r := setupRouter(flatService)
+		r := setupRouter(mock.NewMockHouseService(ctrl), flatService)
 
 		// Execute handler
 		r.ServeHTTP(w, req)
@@ -101,27 +221,63 @@ func TestHandleGetHouse(t *testing.T) {
 		assert.Equal(t, `strconv.ParseInt: parsing "abc": invalid syntax`, response.Error)
 	})
 
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house and flat services
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			GetHouse(gomock.Any(), int64(123)).
+			Return(nil, housePkg.ErrHouseNotExist)
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(houseService, flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, housePkg.ErrHouseNotExist.Error(), response.Error)
+	})
+
 	t.Run("failed to get flats", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		// Setup mock flat service
+		// Setup mock house and flat services
+		houseService := setupHouseService(ctrl)
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			GetFlatListByHouseID(gomock.Any(), int64(123), model.Moderator).
+			GetFlatListByHouseID(gomock.Any(), int64(123), model.Moderator, model.FlatFilter{}).
 			Return(nil, errors.New("internal"))
 
 		// Create HTTP request
 		req := httptest.NewRequest(http.MethodGet, "/house/123", nil)
-		req = req.WithContext(context.WithValue(req.Context(), pkgCtx.KeyUserType, model.Moderator))
+		req = withActor(req, testModerator)
 		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
 
 		// Create HTTP response writer
 		w := httptest.NewRecorder()
 
 		// Create router
-		r := setupRouter(flatService)
+		r := setupRouter(houseService, flatService)
 
 		// Execute handler
 		r.ServeHTTP(w, req)
@@ -12,6 +12,44 @@ import (
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockHouseService is a mock of HouseService interface.
+type MockHouseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHouseServiceMockRecorder
+}
+
+// MockHouseServiceMockRecorder is the mock recorder for MockHouseService.
+type MockHouseServiceMockRecorder struct {
+	mock *MockHouseService
+}
+
+// NewMockHouseService creates a new mock instance.
+func NewMockHouseService(ctrl *gomock.Controller) *MockHouseService {
+	mock := &MockHouseService{ctrl: ctrl}
+	mock.recorder = &MockHouseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHouseService) EXPECT() *MockHouseServiceMockRecorder {
+	return m.recorder
+}
+
+// GetHouse mocks base method.
+func (m *MockHouseService) GetHouse(ctx context.Context, ID int64) (*model.HouseSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouse", ctx, ID)
+	ret0, _ := ret[0].(*model.HouseSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouse indicates an expected call of GetHouse.
+func (mr *MockHouseServiceMockRecorder) GetHouse(ctx, ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouse", reflect.TypeOf((*MockHouseService)(nil).GetHouse), ctx, ID)
+}
+
 // MockFlatService is a mock of FlatService interface.
 type MockFlatService struct {
 	ctrl     *gomock.Controller
@@ -36,16 +74,16 @@ func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
 }
 
 // GetFlatListByHouseID mocks base method.
-func (m *MockFlatService) GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType) ([]*model.Flat, error) {
+func (m *MockFlatService) GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType, filter model.FlatFilter) (*model.FlatPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFlatListByHouseID", ctx, houseID, userRole)
-	ret0, _ := ret[0].([]*model.Flat)
+	ret := m.ctrl.Call(m, "GetFlatListByHouseID", ctx, houseID, userRole, filter)
+	ret0, _ := ret[0].(*model.FlatPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFlatListByHouseID indicates an expected call of GetFlatListByHouseID.
-func (mr *MockFlatServiceMockRecorder) GetFlatListByHouseID(ctx, houseID, userRole interface{}) *gomock.Call {
+func (mr *MockFlatServiceMockRecorder) GetFlatListByHouseID(ctx, houseID, userRole, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatListByHouseID", reflect.TypeOf((*MockFlatService)(nil).GetFlatListByHouseID), ctx, houseID, userRole)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatListByHouseID", reflect.TypeOf((*MockFlatService)(nil).GetFlatListByHouseID), ctx, houseID, userRole, filter)
 }
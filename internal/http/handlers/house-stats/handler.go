@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type FlatService interface {
+	GetHouseStats(ctx context.Context, houseID int64) (*model.HousePriceStats, error)
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleHouseStats"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract house ID from URL parameter
+		houseID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Retrieve price statistics of the house
+		stats, err := flatService.GetHouseStats(r.Context(), houseID)
+		if err != nil {
+			log.Error("failed to get house stats", sl.Err(err))
+			if errors.Is(err, flatPkg.ErrHouseNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the house stats
+		log.Info("successfully get house stats")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, stats)
+	}
+}
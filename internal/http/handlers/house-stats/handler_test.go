@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/house-stats/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Get("/house/{id}/stats", h)
+
+	return r
+}
+
+func TestHandleHouseStats(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stats := &model.HousePriceStats{
+			HouseID: 123,
+			Rooms: []*model.RoomsPriceStats{
+				{Rooms: 1, Count: 3, MinPrice: 100, MaxPrice: 300, AvgPrice: 200, MedianPrice: 200},
+				{Rooms: 2, Count: 2, MinPrice: 300, MaxPrice: 400, AvgPrice: 350, MedianPrice: 350},
+			},
+		}
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetHouseStats(gomock.Any(), int64(123)).
+			Return(stats, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123/stats", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response model.HousePriceStats
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, *stats, response)
+	})
+
+	t.Run("invalid house id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/abc/stats", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetHouseStats(gomock.Any(), int64(123)).
+			Return(nil, flatPkg.ErrHouseNotExist)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123/stats", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, resp.NewError(flatPkg.ErrHouseNotExist), response)
+	})
+
+	t.Run("failed to get stats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			GetHouseStats(gomock.Any(), int64(123)).
+			Return(nil, errors.New("internal error"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/123/stats", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// Assert response body
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal error", response.Message)
+	})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// maxImportRows limits the number of rows accepted in a single import.
+const maxImportRows = 1000
+
+type FlatService interface {
+	ImportFlats(ctx context.Context, rows []model.NewFlat, ownerID uuid.UUID) ([]model.FlatImportResult, error)
+}
+
+// importFlatRow follows the validation rules of the create flat request.
+type importFlatRow struct {
+	HouseID int64 `json:"house_id" validate:"required,gt=0"`
+	Number  int64 `json:"number" validate:"required,gt=0"`
+	Price   int64 `json:"price" validate:"required,gt=0"`
+	Rooms   int64 `json:"rooms" validate:"required,gt=0"`
+	h.FlatAttributesRequest
+}
+
+type importRowReport struct {
+	Row   int    `json:"row"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type importFlatsResponse struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []importRowReport `json:"rows"`
+}
+
+var (
+	errUnsupportedFormat = errors.New("unsupported content type: use text/csv or application/x-ndjson")
+	errEmptyImport       = errors.New("no rows to import")
+	errTooManyRows       = fmt.Errorf("too many rows: at most %d rows per import", maxImportRows)
+)
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleImportFlats"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse the rows according to the content type
+		var (
+			rows []parsedRow
+			err  error
+		)
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			rows, err = parseCSV(r.Body)
+		case "application/x-ndjson", "application/jsonl":
+			rows, err = parseJSONLines(r.Body)
+		default:
+			err = errUnsupportedFormat
+		}
+		if err == nil && len(rows) == 0 {
+			err = errEmptyImport
+		}
+		if err == nil && len(rows) > maxImportRows {
+			err = errTooManyRows
+		}
+		if err != nil {
+			log.Error("failed to parse import", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate every row, only valid rows are passed to the import
+		report := make([]importRowReport, len(rows))
+		var (
+			valid      []model.NewFlat
+			validIndex []int
+		)
+		for i, row := range rows {
+			report[i].Row = row.line
+			if row.err == nil {
+				if err := validate.Struct(row.flat); err != nil {
+					row.err = fmt.Errorf("Validation error: %s", err.(validator.ValidationErrors))
+				}
+			}
+			if row.err != nil {
+				report[i].Error = row.err.Error()
+				continue
+			}
+			valid = append(valid, model.NewFlat{
+				HouseID:        row.flat.HouseID,
+				Number:         row.flat.Number,
+				Price:          row.flat.Price,
+				Rooms:          row.flat.Rooms,
+				FlatAttributes: row.flat.FlatAttributesRequest.Model(),
+			})
+			validIndex = append(validIndex, i)
+		}
+
+		// Import the valid rows on behalf of the authenticated user
+		if len(valid) > 0 {
+			owner := h.ActorFromContext(r.Context())
+			results, err := flatService.ImportFlats(r.Context(), valid, owner.ID)
+			if err != nil {
+				log.Error("failed to import flats", sl.Err(err))
+				h.WriteInternalError(r, w, err)
+				return
+			}
+			for j, res := range results {
+				i := validIndex[j]
+				if res.Err != nil {
+					report[i].Error = res.Err.Error()
+					continue
+				}
+				report[i].ID = res.Flat.ID
+			}
+		}
+
+		// Return the per-row import report
+		response := importFlatsResponse{Rows: report}
+		for _, row := range report {
+			if row.Error != "" {
+				response.Failed++
+			} else {
+				response.Created++
+			}
+		}
+
+		log.Info("flats imported", slog.Int("created", response.Created), slog.Int("failed", response.Failed))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
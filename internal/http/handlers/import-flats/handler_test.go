@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/import-flats/mocks"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOwner = model.Actor{
+	ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+	Role: model.Client,
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test owner
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testOwner.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testOwner.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), flatService)
+
+	// Mount handler on router
+	r.Post("/flat/import", h)
+
+	return r
+}
+
+func TestHandleImportFlats(t *testing.T) {
+	t.Run("csv with invalid rows", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ImportFlats(gomock.Any(), []model.NewFlat{
+				{HouseID: 1, Number: 1, Price: 1000, Rooms: 2},
+				{HouseID: 99, Number: 4, Price: 2000, Rooms: 3},
+			}, testOwner.ID).
+			Return([]model.FlatImportResult{
+				{Flat: &model.Flat{ID: 10}},
+				{Err: flatPkg.ErrHouseNotExist},
+			}, nil)
+
+		// Create HTTP request
+		body := "rooms,house_id,number,price\n" +
+			"2,1,1,1000\n" +
+			"0,1,2,1000\n" +
+			"x,1,3,1000\n" +
+			"3,99,4,2000\n"
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response importFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 3, response.Failed)
+		require.Len(t, response.Rows, 4)
+		assert.Equal(t, importRowReport{Row: 2, ID: 10}, response.Rows[0])
+		assert.Equal(t, 3, response.Rows[1].Row)
+		assert.Contains(t, response.Rows[1].Error, "Validation error")
+		assert.Contains(t, response.Rows[2].Error, "invalid rooms")
+		assert.Equal(t, importRowReport{Row: 5, Error: flatPkg.ErrHouseNotExist.Error()}, response.Rows[3])
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ImportFlats(gomock.Any(), []model.NewFlat{{HouseID: 1, Number: 1, Price: 1000, Rooms: 2}}, testOwner.ID).
+			Return([]model.FlatImportResult{{Flat: &model.Flat{ID: 10}}}, nil)
+
+		// Create HTTP request
+		body := `{"house_id": 1, "number": 1, "price": 1000, "rooms": 2}` + "\n\n" + `{"house_id": 1,` + "\n"
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response importFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 1, response.Failed)
+		assert.Equal(t, importRowReport{Row: 1, ID: 10}, response.Rows[0])
+		assert.Equal(t, 3, response.Rows[1].Row)
+		assert.NotEmpty(t, response.Rows[1].Error)
+	})
+
+	t.Run("csv with attributes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		area, kitchen, floor := 42.5, 9.0, int64(3)
+		layout := model.LayoutEuro2
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ImportFlats(gomock.Any(), []model.NewFlat{
+				{
+					HouseID: 1, Number: 1, Price: 1000, Rooms: 2,
+					FlatAttributes: model.FlatAttributes{TotalArea: &area, KitchenArea: &kitchen, Floor: &floor, Layout: &layout},
+				},
+				{HouseID: 1, Number: 2, Price: 1000, Rooms: 1},
+			}, testOwner.ID).
+			Return([]model.FlatImportResult{
+				{Flat: &model.Flat{ID: 10}},
+				{Flat: &model.Flat{ID: 11}},
+			}, nil)
+
+		// Create HTTP request
+		body := "house_id,number,price,rooms,total_area,kitchen_area,floor,layout\n" +
+			"1,1,1000,2,42.5,9,3,euro_2\n" +
+			"1,2,1000,1,,,,\n" +
+			"1,3,1000,1,40,,x,\n" +
+			"1,4,1000,1,40,,,penthouse\n" +
+			"1,5,1000,1,-5,,,\n"
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response importFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Created)
+		assert.Equal(t, 3, response.Failed)
+		require.Len(t, response.Rows, 5)
+		assert.Equal(t, importRowReport{Row: 2, ID: 10}, response.Rows[0])
+		assert.Equal(t, importRowReport{Row: 3, ID: 11}, response.Rows[1])
+		assert.Contains(t, response.Rows[2].Error, "invalid floor")
+		assert.Contains(t, response.Rows[3].Error, "Validation error")
+		assert.Contains(t, response.Rows[4].Error, "Validation error")
+	})
+
+	t.Run("json lines with attributes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		area, kitchen := 30.0, 35.0
+		description := "quiet yard"
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ImportFlats(gomock.Any(), []model.NewFlat{
+				{
+					HouseID: 1, Number: 1, Price: 1000, Rooms: 2,
+					FlatAttributes: model.FlatAttributes{TotalArea: &area, KitchenArea: &kitchen, Description: &description},
+				},
+			}, testOwner.ID).
+			Return([]model.FlatImportResult{{Err: model.ErrKitchenAreaTooLarge}}, nil)
+
+		// Create HTTP request
+		body := `{"house_id": 1, "number": 1, "price": 1000, "rooms": 2, "total_area": 30, "kitchen_area": 35, "description": "quiet yard"}` + "\n" +
+			`{"house_id": 1, "number": 2, "price": 1000, "rooms": 2, "floor": 500}` + "\n"
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response importFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 0, response.Created)
+		assert.Equal(t, 2, response.Failed)
+		assert.Equal(t, importRowReport{Row: 1, Error: model.ErrKitchenAreaTooLarge.Error()}, response.Rows[0])
+		assert.Contains(t, response.Rows[1].Error, "Validation error")
+	})
+
+	t.Run("no valid rows", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader("house_id,number,price,rooms\n0,0,0,0\n"))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response importFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 0, response.Created)
+		assert.Equal(t, 1, response.Failed)
+	})
+
+	t.Run("invalid csv header", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader("house_id,number,price\n1,1,1000\n"))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Error, "missing column rooms")
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPost, "/flat/import", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/xml")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, errUnsupportedFormat.Error(), response.Error)
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parsedRow is a single row of the import along with its line number
+// and the parse error, if any.
+type parsedRow struct {
+	line int
+	flat importFlatRow
+	err  error
+}
+
+var csvColumns = []string{"house_id", "number", "price", "rooms"}
+
+// parseCSV parses rows of a CSV file. The first line must be a header
+// naming the house_id, number, price and rooms columns in any order.
+// The total_area, kitchen_area, floor, description and layout columns
+// are optional, empty cells leave the attribute unset.
+func parseCSV(body io.Reader) ([]parsedRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("invalid csv header: missing column %s", name)
+		}
+	}
+
+	var rows []parsedRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, parsedRow{line: parseErr.StartLine, err: parseErr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := parsedRow{line: line}
+
+		values := make([]int64, len(csvColumns))
+		for i, name := range csvColumns {
+			values[i], err = strconv.ParseInt(strings.TrimSpace(record[index[name]]), 10, 64)
+			if err != nil {
+				row.err = fmt.Errorf("invalid %s: %w", name, err)
+				break
+			}
+		}
+		row.flat = importFlatRow{HouseID: values[0], Number: values[1], Price: values[2], Rooms: values[3]}
+		if row.err == nil {
+			row.flat.FlatAttributesRequest, row.err = parseCSVAttributes(record, index)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseCSVAttributes parses the optional attribute columns of the record.
+func parseCSVAttributes(record []string, index map[string]int) (attributes h.FlatAttributesRequest, err error) {
+	cell := func(name string) string {
+		i, ok := index[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	if value := cell("total_area"); value != "" {
+		area, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return attributes, fmt.Errorf("invalid total_area: %w", err)
+		}
+		attributes.TotalArea = &area
+	}
+	if value := cell("kitchen_area"); value != "" {
+		area, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return attributes, fmt.Errorf("invalid kitchen_area: %w", err)
+		}
+		attributes.KitchenArea = &area
+	}
+	if value := cell("floor"); value != "" {
+		floor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return attributes, fmt.Errorf("invalid floor: %w", err)
+		}
+		attributes.Floor = &floor
+	}
+	if value := cell("description"); value != "" {
+		attributes.Description = &value
+	}
+	if value := cell("layout"); value != "" {
+		attributes.Layout = &value
+	}
+
+	return attributes, nil
+}
+
+// parseJSONLines parses rows of a JSON lines document, skipping blank lines.
+func parseJSONLines(body io.Reader) ([]parsedRow, error) {
+	scanner := bufio.NewScanner(body)
+
+	var rows []parsedRow
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := parsedRow{line: line}
+		row.err = json.Unmarshal(data, &row.flat)
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	ModerateFlats(ctx context.Context, IDs []int64, update model.FlatStatusUpdate, actor model.Actor) ([]model.FlatModerationResult, error)
+}
+
+type moderateFlatsRequest struct {
+	IDs     []int64 `json:"ids" validate:"required,min=1,max=100,unique,dive,gt=0"`
+	Status  string  `json:"status" validate:"required,oneof=approved declined"`
+	Reason  string  `json:"reason" validate:"required_if=Status declined"`
+	Comment string  `json:"comment" validate:"max=1000"`
+}
+
+type flatReport struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type moderateFlatsResponse struct {
+	Updated int          `json:"updated"`
+	Failed  int          `json:"failed"`
+	Flats   []flatReport `json:"flats"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleModerateFlats"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Decode the request body into a moderateFlatsRequest struct
+		var req moderateFlatsRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("invalid json", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		update := model.FlatStatusUpdate{Status: model.FlatStatus(req.Status)}
+
+		// Parse the decline reason
+		if update.Status == model.StatusDeclined {
+			update.Reason, err = model.ParseDeclineReason(req.Reason)
+			if err != nil {
+				log.Error("invalid decline reason in request", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			update.Comment = req.Comment
+		}
+
+		// Moderate the flats on behalf of the authenticated moderator
+		results, err := flatService.ModerateFlats(r.Context(), req.IDs, update, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to moderate flats", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the per-flat report
+		response := moderateFlatsResponse{Flats: make([]flatReport, 0, len(results))}
+		for _, res := range results {
+			report := flatReport{ID: res.FlatID}
+			if res.Err != nil {
+				report.Error = res.Err.Error()
+				response.Failed++
+			} else {
+				report.Status = string(res.Flat.Status)
+				response.Updated++
+			}
+			response.Flats = append(response.Flats, report)
+		}
+
+		log.Info("flats moderated", slog.Int("updated", response.Updated), slog.Int("failed", response.Failed))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/moderate-flats/mocks"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testModerator = model.Actor{
+	ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+	Role: model.Moderator,
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test moderator
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testModerator.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testModerator.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), flatService)
+
+	// Mount handler on router
+	r.Post("/flat/moderate", h)
+
+	return r
+}
+
+func TestHandleModerateFlats(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			ModerateFlats(gomock.Any(), []int64{1, 2}, model.FlatStatusUpdate{
+				Status:  model.StatusDeclined,
+				Reason:  model.ReasonDuplicate,
+				Comment: "same as 5",
+			}, testModerator).
+			Return([]model.FlatModerationResult{
+				{FlatID: 1, Flat: &model.Flat{ID: 1, Status: model.StatusDeclined}},
+				{FlatID: 2, Err: flatPkg.ErrFlatNotExist},
+			}, nil)
+
+		// Create HTTP request
+		reqBody := []byte(`{"ids": [1, 2], "status": "declined", "reason": "duplicate", "comment": "same as 5"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/moderate", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response moderateFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, moderateFlatsResponse{
+			Updated: 1,
+			Failed:  1,
+			Flats: []flatReport{
+				{ID: 1, Status: "declined"},
+				{ID: 2, Error: flatPkg.ErrFlatNotExist.Error()},
+			},
+		}, response)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		cases := []struct {
+			name string
+			body string
+		}{
+			{"empty ids", `{"ids": [], "status": "approved"}`},
+			{"duplicate ids", `{"ids": [1, 1], "status": "approved"}`},
+			{"unsupported status", `{"ids": [1], "status": "on_moderation"}`},
+			{"decline without reason", `{"ids": [1], "status": "declined"}`},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				// Setup mock flat service
+				flatService := mock.NewMockFlatService(ctrl)
+
+				// Create HTTP request
+				req := httptest.NewRequest(http.MethodPost, "/flat/moderate", bytes.NewReader([]byte(tc.body)))
+
+				// Create HTTP response writer
+				w := httptest.NewRecorder()
+
+				// Execute handler
+				setupRouter(flatService).ServeHTTP(w, req)
+
+				// Assert response
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+
+				var response resp.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Contains(t, response.Error, "Validation error")
+			})
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	ModerationQueue(ctx context.Context, limit int64) ([]*model.Flat, error)
+}
+
+type moderationQueueRequest struct {
+	Limit int64 `validate:"omitempty,gte=1,lte=100"`
+}
+
+type moderationQueueResponse struct {
+	Flats []*model.Flat `json:"flats"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleModerationQueue"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse the page size
+		limit, err := query.Int64(r.URL.Query(), "limit")
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req := moderationQueueRequest{Limit: limit}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		// Retrieve flats waiting for moderation
+		flatList, err := flatService.ModerationQueue(r.Context(), req.Limit)
+		if err != nil {
+			log.Error("failed to get moderation queue", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the moderation queue
+		log.Info("successfully get moderation queue")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, moderationQueueResponse{
+			Flats: flatList,
+		})
+	}
+}
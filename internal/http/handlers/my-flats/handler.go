@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	OwnerFlats(ctx context.Context, owner model.Actor, filter model.FlatFilter) (*model.FlatPage, error)
+}
+
+type myFlatsRequest struct {
+	Statuses []string `validate:"dive,oneof=created approved declined on_moderation archived sold"`
+	Sort     string   `validate:"omitempty,oneof=id_asc id_desc price_asc price_desc rooms_asc rooms_desc"`
+	Limit    int64    `validate:"omitempty,gte=1,lte=100"`
+	Cursor   string
+	h.FlatAttributesQuery
+}
+
+type myFlatsResponse struct {
+	Flats      []*model.Flat `json:"flats"`
+	Total      int64         `json:"total"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleMyFlats"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse filter and pagination params
+		var req myFlatsRequest
+		var err error
+		req.Limit, err = query.Int64(r.URL.Query(), "limit")
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.FlatAttributesQuery, err = h.ParseFlatAttributesQuery(r.URL.Query())
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.Statuses = query.StringList(r.URL.Query(), "status")
+		req.Sort = r.URL.Query().Get("sort")
+		req.Cursor = r.URL.Query().Get("cursor")
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		filter := model.FlatFilter{
+			Sort:  model.FlatSort(req.Sort),
+			Limit: req.Limit,
+		}
+		req.FlatAttributesQuery.Apply(&filter)
+		for _, status := range req.Statuses {
+			filter.Statuses = append(filter.Statuses, model.FlatStatus(status))
+		}
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
+		// Retrieve a page of flats created by the authenticated user
+		owner := h.ActorFromContext(r.Context())
+		page, err := flatService.OwnerFlats(r.Context(), owner, filter)
+		if err != nil {
+			log.Error("failed to get owner flats", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the list of flats without the fields hidden from the owner
+		for _, flat := range page.Flats {
+			flat.HideFrom(owner)
+		}
+		log.Info("successfully get owner flats")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, myFlatsResponse{
+			Flats:      page.Flats,
+			Total:      page.Total,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
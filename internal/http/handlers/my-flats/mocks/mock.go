@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/http/handlers/my-flats/handler.go
+
+// Package mock_handlers is a generated GoMock package.
+package mock_handlers
+
+import (
+	model "avito-backend-bootcamp/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFlatService is a mock of FlatService interface.
+type MockFlatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatServiceMockRecorder
+}
+
+// MockFlatServiceMockRecorder is the mock recorder for MockFlatService.
+type MockFlatServiceMockRecorder struct {
+	mock *MockFlatService
+}
+
+// NewMockFlatService creates a new mock instance.
+func NewMockFlatService(ctrl *gomock.Controller) *MockFlatService {
+	mock := &MockFlatService{ctrl: ctrl}
+	mock.recorder = &MockFlatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
+	return m.recorder
+}
+
+// OwnerFlats mocks base method.
+func (m *MockFlatService) OwnerFlats(ctx context.Context, owner model.Actor, filter model.FlatFilter) (*model.FlatPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerFlats", ctx, owner, filter)
+	ret0, _ := ret[0].(*model.FlatPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerFlats indicates an expected call of OwnerFlats.
+func (mr *MockFlatServiceMockRecorder) OwnerFlats(ctx, owner, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerFlats", reflect.TypeOf((*MockFlatService)(nil).OwnerFlats), ctx, owner, filter)
+}
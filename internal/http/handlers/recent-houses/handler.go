@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type HouseService interface {
+	SearchHouses(ctx context.Context, filter model.HouseFilter) (*model.HousePage, error)
+}
+
+type recentHousesRequest struct {
+	Limit  int64 `validate:"omitempty,gte=1,lte=100"`
+	Cursor string
+}
+
+type houseResponse struct {
+	ID                  int64      `json:"id"`
+	Address             string     `json:"address"`
+	Year                int64      `json:"year"`
+	Developer           *string    `json:"developer,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	LastFlatPublishedAt *time.Time `json:"last_flat_published_at"`
+}
+
+type recentHousesResponse struct {
+	Houses     []houseResponse `json:"houses"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, houseService HouseService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleRecentHouses"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse pagination params
+		var req recentHousesRequest
+		var err error
+		req.Limit, err = query.Int64(r.URL.Query(), "limit")
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+		req.Cursor = r.URL.Query().Get("cursor")
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		// Newest publications go first
+		filter := model.HouseFilter{
+			Sort:  model.SortPublishedAtDesc,
+			Limit: req.Limit,
+		}
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
+		// Retrieve the houses with recently published flats
+		page, err := houseService.SearchHouses(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to get recent houses", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the found houses
+		log.Info("recent houses retrieved")
+		houses := make([]houseResponse, 0, len(page.Houses))
+		for _, house := range page.Houses {
+			houses = append(houses, houseResponse{
+				ID:                  house.ID,
+				Address:             house.Address,
+				Year:                house.YearOfConstruction,
+				Developer:           dbUtil.FromNullString(house.Developer),
+				CreatedAt:           house.CreatedAt,
+				UpdatedAt:           house.UpdatedAt,
+				LastFlatPublishedAt: house.LastFlatPublishedAt,
+			})
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, recentHousesResponse{
+			Houses:     houses,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/recent-houses/mocks"
+	"avito-backend-bootcamp/internal/model"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(houseService HouseService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), houseService)
+
+	// Mount handler on router
+	r.Get("/house/recent", h)
+
+	return r
+}
+
+func TestHandleRecentHouses(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cursor := model.Cursor{Value: 1700000000000000, ID: 7}
+		createdAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+		publishedAt := createdAt.Add(time.Hour)
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{
+				Sort:  model.SortPublishedAtDesc,
+				Limit: 10,
+				After: &cursor,
+			}).
+			Return(&model.HousePage{
+				Houses: []*model.House{{
+					ID:                  5,
+					Address:             "some address",
+					YearOfConstruction:  2020,
+					CreatedAt:           createdAt,
+					UpdatedAt:           publishedAt,
+					LastFlatPublishedAt: &publishedAt,
+				}},
+				NextCursor: "next",
+			}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/recent?limit=10&cursor="+cursor.Encode(), nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response recentHousesResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, recentHousesResponse{
+			Houses: []houseResponse{{
+				ID:                  5,
+				Address:             "some address",
+				Year:                2020,
+				CreatedAt:           createdAt,
+				UpdatedAt:           publishedAt,
+				LastFlatPublishedAt: &publishedAt,
+			}},
+			NextCursor: "next",
+		}, response)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house/recent?limit=1000", nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Error, "Validation error")
+	})
+}
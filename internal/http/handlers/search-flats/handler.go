@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type FlatService interface {
+	SearchFlats(ctx context.Context, filter model.FlatFilter, userRole model.UserType) (*model.FlatPage, error)
+}
+
+type searchFlatsRequest struct {
+	HouseIDs  []int64 `validate:"dive,gt=0"`
+	Rooms     []int64 `validate:"dive,gt=0"`
+	PriceMin  int64   `validate:"gte=0"`
+	PriceMax  int64   `validate:"omitempty,gtefield=PriceMin"`
+	Developer string
+	YearMin   int64  `validate:"gte=0"`
+	YearMax   int64  `validate:"omitempty,gtefield=YearMin"`
+	Sort      string `validate:"omitempty,oneof=id_asc id_desc price_asc price_desc rooms_asc rooms_desc"`
+	Limit     int64  `validate:"omitempty,gte=1,lte=100"`
+	Cursor    string
+	h.FlatAttributesQuery
+}
+
+type searchFlatsResponse struct {
+	Flats      []*model.Flat `json:"flats"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleSearchFlats"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse query params into a SearchFlatsRequest struct
+		req, err := parseRequest(r.URL.Query())
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		// Build the search filter
+		filter := model.FlatFilter{
+			HouseIDs:  req.HouseIDs,
+			Rooms:     req.Rooms,
+			PriceMin:  req.PriceMin,
+			PriceMax:  req.PriceMax,
+			Developer: req.Developer,
+			YearMin:   req.YearMin,
+			YearMax:   req.YearMax,
+			Sort:      model.FlatSort(req.Sort),
+			Limit:     req.Limit,
+		}
+		req.FlatAttributesQuery.Apply(&filter)
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
+		// Extract audience from the context
+		actor := h.ActorFromContext(r.Context())
+
+		// Search flats
+		page, err := flatService.SearchFlats(r.Context(), filter, actor.Role)
+		if err != nil {
+			log.Error("failed to search flats", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the found flats without the fields hidden from the user
+		for _, flat := range page.Flats {
+			flat.HideFrom(actor)
+		}
+		log.Info("flats search success")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, searchFlatsResponse{
+			Flats:      page.Flats,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
+
+func parseRequest(values url.Values) (req searchFlatsRequest, err error) {
+	if req.HouseIDs, err = query.Int64List(values, "house_id"); err != nil {
+		return req, err
+	}
+	if req.Rooms, err = query.Int64List(values, "rooms"); err != nil {
+		return req, err
+	}
+	if req.PriceMin, err = query.Int64(values, "price_from"); err != nil {
+		return req, err
+	}
+	if req.PriceMax, err = query.Int64(values, "price_to"); err != nil {
+		return req, err
+	}
+	if req.YearMin, err = query.Int64(values, "year_from"); err != nil {
+		return req, err
+	}
+	if req.YearMax, err = query.Int64(values, "year_to"); err != nil {
+		return req, err
+	}
+	if req.Limit, err = query.Int64(values, "limit"); err != nil {
+		return req, err
+	}
+	if req.FlatAttributesQuery, err = h.ParseFlatAttributesQuery(values); err != nil {
+		return req, err
+	}
+	req.Developer = values.Get("developer")
+	req.Sort = values.Get("sort")
+	req.Cursor = values.Get("cursor")
+
+	return req, nil
+}
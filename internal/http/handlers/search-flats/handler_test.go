@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/search-flats/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testClient    = model.Actor{ID: uuid.MustParse("3c9d2e71-5a4b-4c8f-9e16-2f7a0b8d4c53"), Role: model.Client}
+	testModerator = model.Actor{ID: uuid.MustParse("8e4a1f06-7b2c-4d9e-a3f5-6c0b9d2e1a74"), Role: model.Moderator}
+)
+
+// withActor puts the authenticated user into the request context.
+func withActor(req *http.Request, actor model.Actor) *http.Request {
+	ctx := context.WithValue(req.Context(), pkgCtx.KeyUserID, actor.ID)
+	ctx = context.WithValue(ctx, pkgCtx.KeyUserType, actor.Role)
+	return req.WithContext(ctx)
+}
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), flatService)
+
+	// Mount handler on router
+	r.Get("/flat/search", h)
+
+	return r
+}
+
+func TestHandleSearchFlats(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cursor := model.Cursor{Value: 5000000, ID: 7}
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			SearchFlats(gomock.Any(), model.FlatFilter{
+				HouseIDs:  []int64{1, 2},
+				Rooms:     []int64{2},
+				PriceMax:  10000000,
+				Developer: "PIK",
+				YearMin:   2010,
+				Sort:      model.SortPriceAsc,
+				Limit:     10,
+				After:     &cursor,
+			}, model.Client).
+			Return(&model.FlatPage{
+				Flats:      []*model.Flat{{ID: 8, Price: 6000000, Rooms: 2}},
+				NextCursor: "next",
+			}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet,
+			"/flat/search?house_id=1,2&rooms=2&price_to=10000000&developer=PIK&year_from=2010"+
+				"&sort=price_asc&limit=10&cursor="+cursor.Encode(), nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response searchFlatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, searchFlatsResponse{
+			Flats:      []*model.Flat{{ID: 8, Price: 6000000, Rooms: 2}},
+			NextCursor: "next",
+		}, response)
+	})
+
+	t.Run("owner and flag visibility", func(t *testing.T) {
+		otherOwnerID := uuid.New()
+
+		tests := []struct {
+			name     string
+			actor    model.Actor
+			expected []*model.Flat
+		}{
+			{
+				name:  "client sees only own owner",
+				actor: testClient,
+				expected: []*model.Flat{
+					{ID: 1, OwnerID: &testClient.ID},
+					{ID: 2},
+				},
+			},
+			{
+				name:  "moderator sees owners and flags",
+				actor: testModerator,
+				expected: []*model.Flat{
+					{ID: 1, OwnerID: &testClient.ID, Flagged: true},
+					{ID: 2, OwnerID: &otherOwnerID, Flagged: true},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				// Setup mock flat service
+				flatService := mock.NewMockFlatService(ctrl)
+				flatService.
+					EXPECT().
+					SearchFlats(gomock.Any(), model.FlatFilter{}, tt.actor.Role).
+					Return(&model.FlatPage{Flats: []*model.Flat{
+						{ID: 1, OwnerID: &testClient.ID, Flagged: true},
+						{ID: 2, OwnerID: &otherOwnerID, Flagged: true},
+					}}, nil)
+
+				// Create HTTP request
+				req := httptest.NewRequest(http.MethodGet, "/flat/search", nil)
+				req = withActor(req, tt.actor)
+
+				// Create HTTP response writer
+				w := httptest.NewRecorder()
+
+				// Create router
+				r := setupRouter(flatService)
+
+				// Execute handler
+				r.ServeHTTP(w, req)
+
+				// Assert response
+				assert.Equal(t, http.StatusOK, w.Code)
+				var response searchFlatsResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, response.Flats)
+			})
+		}
+	})
+
+	t.Run("attribute filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			SearchFlats(gomock.Any(), model.FlatFilter{
+				AreaMin:  40.5,
+				AreaMax:  80,
+				FloorMin: 2,
+				Layouts:  []model.FlatLayout{model.LayoutStudio, model.LayoutEuro2},
+			}, model.Client).
+			Return(&model.FlatPage{Flats: []*model.Flat{}}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet,
+			"/flat/search?area_from=40.5&area_to=80&floor_from=2&layout=studio,euro_2", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/search?layout=penthouse", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid price range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/search?price_from=200&price_to=100", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/search?cursor=???", nil)
+		req = withActor(req, testClient)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, model.ErrInvalidCursor.Error(), response.Error)
+	})
+
+	t.Run("failed to search flats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any(), model.Moderator).
+			Return(nil, errors.New("internal"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/flat/search", nil)
+		req = withActor(req, testModerator)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// Assert response body
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, handlers.ErrorResponse{
+			Message:   "internal",
+			RequestID: "test",
+			Code:      http.StatusInternalServerError,
+		}, response)
+	})
+}
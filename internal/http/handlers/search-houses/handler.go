@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	"avito-backend-bootcamp/pkg/utils/query"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type HouseService interface {
+	SearchHouses(ctx context.Context, filter model.HouseFilter) (*model.HousePage, error)
+}
+
+type searchHousesRequest struct {
+	Developer string
+	YearMin   int64  `validate:"gte=0"`
+	YearMax   int64  `validate:"omitempty,gtefield=YearMin"`
+	Address   string `validate:"max=200"`
+	Sort      string `validate:"omitempty,oneof=updated_at_desc updated_at_asc"`
+	Limit     int64  `validate:"omitempty,gte=1,lte=100"`
+	Cursor    string
+}
+
+type houseResponse struct {
+	ID                  int64      `json:"id"`
+	Address             string     `json:"address"`
+	Year                int64      `json:"year"`
+	Developer           *string    `json:"developer,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	LastFlatPublishedAt *time.Time `json:"last_flat_published_at,omitempty"`
+}
+
+type searchHousesResponse struct {
+	Houses     []houseResponse `json:"houses"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func New(log *slog.Logger, validate *validator.Validate, houseService HouseService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleSearchHouses"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Parse query params into a SearchHousesRequest struct
+		req, err := parseRequest(r.URL.Query())
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+
+		// Build the search filter
+		filter := model.HouseFilter{
+			Developer: req.Developer,
+			YearMin:   req.YearMin,
+			YearMax:   req.YearMax,
+			Address:   req.Address,
+			Sort:      model.HouseSort(req.Sort),
+			Limit:     req.Limit,
+		}
+		if req.Cursor != "" {
+			filter.After, err = model.ParseCursor(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+
+		// Search houses
+		page, err := houseService.SearchHouses(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to search houses", sl.Err(err))
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the found houses
+		log.Info("houses search success")
+		houses := make([]houseResponse, 0, len(page.Houses))
+		for _, house := range page.Houses {
+			houses = append(houses, houseResponse{
+				ID:                  house.ID,
+				Address:             house.Address,
+				Year:                house.YearOfConstruction,
+				Developer:           dbUtil.FromNullString(house.Developer),
+				CreatedAt:           house.CreatedAt,
+				UpdatedAt:           house.UpdatedAt,
+				LastFlatPublishedAt: house.LastFlatPublishedAt,
+			})
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, searchHousesResponse{
+			Houses:     houses,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
+
+func parseRequest(values url.Values) (req searchHousesRequest, err error) {
+	if req.YearMin, err = query.Int64(values, "year_from"); err != nil {
+		return req, err
+	}
+	if req.YearMax, err = query.Int64(values, "year_to"); err != nil {
+		return req, err
+	}
+	if req.Limit, err = query.Int64(values, "limit"); err != nil {
+		return req, err
+	}
+	req.Developer = values.Get("developer")
+	req.Address = values.Get("address")
+	req.Sort = values.Get("sort")
+	req.Cursor = values.Get("cursor")
+
+	return req, nil
+}
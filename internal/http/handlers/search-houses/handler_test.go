@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"avito-backend-bootcamp/internal/http/handlers"
+	mock "avito-backend-bootcamp/internal/http/handlers/search-houses/mocks"
+	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/model"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(houseService HouseService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), houseService)
+
+	// Mount handler on router
+	r.Get("/house", h)
+
+	return r
+}
+
+func TestHandleSearchHouses(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cursor := model.Cursor{Value: 1700000000000000, ID: 7}
+		updatedAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{
+				Developer: "PIK",
+				YearMin:   2010,
+				YearMax:   2020,
+				Address:   "Lenina",
+				Sort:      model.SortUpdatedAtAsc,
+				Limit:     10,
+				After:     &cursor,
+			}).
+			Return(&model.HousePage{
+				Houses: []*model.House{{
+					ID:                 8,
+					Address:            "Lenina 1",
+					YearOfConstruction: 2015,
+					Developer:          sql.NullString{String: "PIK", Valid: true},
+					UpdatedAt:          updatedAt,
+				}},
+				NextCursor: "next",
+			}, nil)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet,
+			"/house?developer=PIK&year_from=2010&year_to=2020&address="+url.QueryEscape("Lenina")+
+				"&sort=updated_at_asc&limit=10&cursor="+cursor.Encode(), nil)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response searchHousesResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		developer := "PIK"
+		assert.Equal(t, searchHousesResponse{
+			Houses: []houseResponse{{
+				ID:        8,
+				Address:   "Lenina 1",
+				Year:      2015,
+				Developer: &developer,
+				UpdatedAt: updatedAt,
+			}},
+			NextCursor: "next",
+		}, response)
+	})
+
+	invalidCases := []struct {
+		name  string
+		query string
+	}{
+		{"year range reversed", "year_from=2020&year_to=2010"},
+		{"unknown sort", "sort=address_asc"},
+		{"limit too large", "limit=1000"},
+		{"invalid cursor", "cursor=%21%21"},
+		{"invalid year", "year_from=abc"},
+	}
+
+	for _, tc := range invalidCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock house service
+			houseService := mock.NewMockHouseService(ctrl)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodGet, "/house?"+tc.query, nil)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(houseService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.NotEmpty(t, response.Error)
+		})
+	}
+
+	t.Run("failed to search houses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		houseService.
+			EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{}).
+			Return(nil, errors.New("internal error"))
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodGet, "/house", nil)
+		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// Assert response body
+		var response handlers.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "internal error", response.Message)
+	})
+}
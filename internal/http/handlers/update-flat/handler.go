@@ -18,22 +18,31 @@ import (
 )
 
 type FlatService interface {
-	UpdateFlat(ctx context.Context, ID int64, status model.FlatStatus) (*model.Flat, error)
+	UpdateFlat(ctx context.Context, ID int64, update model.FlatStatusUpdate, actor model.Actor) (*model.Flat, error)
 }
 
 type updateFlatRequest struct {
-	ID     int64  `json:"id" validate:"required,gt=0"`
-	Status string `json:"status" validate:"required"`
+	ID      int64  `json:"id" validate:"required,gt=0"`
+	Status  string `json:"status" validate:"required"`
+	Reason  string `json:"reason" validate:"required_if=Status declined"`
+	Comment string `json:"comment" validate:"max=1000"`
+	Version int64  `json:"version" validate:"omitempty,gt=0"`
 }
 
 type updateFlatResponse struct {
-	ID      int64  `json:"id"`
-	HouseID int64  `json:"house_id"`
-	Price   int64  `json:"price"`
-	Rooms   int64  `json:"rooms"`
-	Status  string `json:"status"`
+	ID             int64   `json:"id"`
+	HouseID        int64   `json:"house_id"`
+	Number         int64   `json:"number"`
+	Price          int64   `json:"price"`
+	Rooms          int64   `json:"rooms"`
+	Status         string  `json:"status"`
+	DeclineReason  *string `json:"decline_reason,omitempty"`
+	DeclineComment *string `json:"decline_comment,omitempty"`
+	Version        int64   `json:"version"`
 }
 
+var errVersionRequired = errors.New("flat version is required: pass If-Match header or version field")
+
 func New(log *slog.Logger, validate *validator.Validate, flatService FlatService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Setup logger
@@ -71,28 +80,78 @@ func New(log *slog.Logger, validate *validator.Validate, flatService FlatService
 			return
 		}
 
-		// Update the flat
-		flat, err := flatService.UpdateFlat(r.Context(), req.ID, status)
+		update := model.FlatStatusUpdate{Status: status, Version: req.Version}
+
+		// The If-Match header takes precedence over the version field
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+			update.Version, err = h.ParseETag(ifMatch)
+			if err != nil {
+				log.Error("invalid If-Match header", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+		}
+		if update.Version == 0 {
+			log.Error("flat version is missing")
+			render.Status(r, http.StatusPreconditionRequired)
+			render.JSON(w, r, resp.NewError(errVersionRequired))
+			return
+		}
+
+		// Parse the decline reason
+		if status == model.StatusDeclined {
+			update.Reason, err = model.ParseDeclineReason(req.Reason)
+			if err != nil {
+				log.Error("invalid decline reason in request", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			update.Comment = req.Comment
+		}
+
+		// Update the flat on behalf of the authenticated user
+		flat, err := flatService.UpdateFlat(r.Context(), req.ID, update, h.ActorFromContext(r.Context()))
 		if err != nil {
 			log.Error("failed to update flat", sl.Err(err))
-			if errors.Is(err, flatPkg.ErrFlatNotExist) || errors.Is(err, model.ErrImpossibleTransition) {
+			if errors.Is(err, flatPkg.ErrFlatNotExist) ||
+				errors.Is(err, model.ErrImpossibleTransition) ||
+				errors.Is(err, model.ErrDeclineReasonMissing) {
 				render.Status(r, http.StatusBadRequest)
 				render.JSON(w, r, resp.NewError(err))
 				return
 			}
+			if errors.Is(err, model.ErrForeignModeration) ||
+				errors.Is(err, model.ErrTransitionForbidden) {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, flatPkg.ErrFlatModified) ||
+				errors.Is(err, model.ErrFlatOnModeration) {
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
 			h.WriteInternalError(r, w, err)
 			return
 		}
 
 		// Return the updated flat details
 		log.Info("flat updated succesfully")
+		w.Header().Set("ETag", h.ETag(flat.Version))
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, updateFlatResponse{
-			ID:      flat.ID,
-			HouseID: flat.HouseID,
-			Price:   flat.Price,
-			Status:  string(flat.Status),
-			Rooms:   flat.Rooms,
+			ID:             flat.ID,
+			HouseID:        flat.HouseID,
+			Number:         flat.Number,
+			Price:          flat.Price,
+			Status:         string(flat.Status),
+			Rooms:          flat.Rooms,
+			DeclineReason:  (*string)(flat.DeclineReason),
+			DeclineComment: flat.DeclineComment,
+			Version:        flat.Version,
 		})
 	}
 }
@@ -14,20 +14,36 @@ import (
 	mwr "avito-backend-bootcamp/internal/http/middleware"
 	"avito-backend-bootcamp/internal/model"
 	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
 	resp "avito-backend-bootcamp/pkg/utils/response"
 	"avito-backend-bootcamp/pkg/utils/sl"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var testModerator = model.Actor{
+	ID:   uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11"),
+	Role: model.Moderator,
+}
+
 func setupRouter(flatService FlatService) *chi.Mux {
 	// Create router
 	r := chi.NewRouter()
 
+	// Authenticate requests as the test moderator
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testModerator.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testModerator.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
 	// Create handler
 	h := New(sl.SetupLogger(), validator.New(), flatService)
 
@@ -46,12 +62,13 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			UpdateFlat(gomock.Any(), int64(123), model.StatusApproved).
-			Return(&model.Flat{ID: 123, HouseID: 456, Price: 1000000, Rooms: 2, Status: model.StatusApproved}, nil)
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusApproved, Version: 3}, testModerator).
+			Return(&model.Flat{ID: 123, HouseID: 456, Price: 1000000, Rooms: 2, Status: model.StatusApproved, Version: 4}, nil)
 
 		// Create HTTP request
 		reqBody := []byte(`{"id": 123, "status": "approved"}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+		req.Header.Set("If-Match", `"3"`)
 
 		// Create HTTP response writer
 		w := httptest.NewRecorder()
@@ -64,6 +81,7 @@ func TestHandleUpdateFlat(t *testing.T) {
 
 		// Assert response status code
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
 
 		// Assert response body
 		var response updateFlatResponse
@@ -75,9 +93,116 @@ func TestHandleUpdateFlat(t *testing.T) {
 			Price:   1000000,
 			Rooms:   2,
 			Status:  "approved",
+			Version: 4,
 		}, response)
 	})
 
+	t.Run("decline with reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reason := model.ReasonDuplicate
+		comment := "same as flat 100"
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{
+				Status:  model.StatusDeclined,
+				Reason:  reason,
+				Comment: comment,
+				Version: 3,
+			}, testModerator).
+			Return(&model.Flat{
+				ID: 123, HouseID: 456, Price: 1000000, Rooms: 2, Status: model.StatusDeclined,
+				DeclineReason: &reason, DeclineComment: &comment,
+			}, nil)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "declined", "version": 3, "reason": "duplicate", "comment": "same as flat 100"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Assert response body
+		var response updateFlatResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "duplicate", *response.DeclineReason)
+		assert.Equal(t, comment, *response.DeclineComment)
+	})
+
+	t.Run("decline without reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "declined", "version": 3}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Error, "Validation error")
+	})
+
+	t.Run("unknown decline reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "declined", "version": 3, "reason": "ugly"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "unknown enum value ugly", response.Error)
+	})
+
 	t.Run("invalid json", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -115,7 +240,7 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 
 		// Create HTTP request
-		reqBody := []byte(`{"id": 0, "status": "approved"}`)
+		reqBody := []byte(`{"id": 0, "status": "approved", "version": 3}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -145,7 +270,7 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 
 		// Create HTTP request
-		reqBody := []byte(`{"id": 123, "status": "invalid"}`)
+		reqBody := []byte(`{"id": 123, "status": "invalid", "version": 3}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -175,11 +300,11 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			UpdateFlat(gomock.Any(), int64(123), model.StatusApproved).
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusApproved, Version: 3}, testModerator).
 			Return(nil, flatPkg.ErrFlatNotExist)
 
 		// Create HTTP request
-		reqBody := []byte(`{"id": 123, "status": "approved"}`)
+		reqBody := []byte(`{"id": 123, "status": "approved", "version": 3}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -209,11 +334,11 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			UpdateFlat(gomock.Any(), int64(123), model.StatusApproved).
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusApproved, Version: 3}, testModerator).
 			Return(nil, model.ErrImpossibleTransition)
 
 		// Create HTTP request
-		reqBody := []byte(`{"id": 123, "status": "approved"}`)
+		reqBody := []byte(`{"id": 123, "status": "approved", "version": 3}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
 
 		// Create HTTP response writer
@@ -235,6 +360,71 @@ func TestHandleUpdateFlat(t *testing.T) {
 		assert.Equal(t, model.ErrImpossibleTransition.Error(), response.Error)
 	})
 
+	t.Run("foreign moderation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusDeclined, Reason: model.ReasonOther, Version: 3}, testModerator).
+			Return(nil, model.ErrForeignModeration)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "declined", "version": 3, "reason": "other"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Create router
+		r := setupRouter(flatService)
+
+		// Execute handler
+		r.ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, model.ErrForeignModeration.Error(), response.Error)
+	})
+
+	t.Run("transition forbidden", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusCreated, Version: 3}, testModerator).
+			Return(nil, model.ErrTransitionForbidden)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "created", "version": 3}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response status code
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Assert response body
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, model.ErrTransitionForbidden.Error(), response.Error)
+	})
+
 	t.Run("failed to update flat", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -243,11 +433,11 @@ func TestHandleUpdateFlat(t *testing.T) {
 		flatService := mock.NewMockFlatService(ctrl)
 		flatService.
 			EXPECT().
-			UpdateFlat(gomock.Any(), int64(123), model.StatusApproved).
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusApproved, Version: 3}, testModerator).
 			Return(nil, errors.New("internal error"))
 
 		// Create HTTP request
-		reqBody := []byte(`{"id": 123, "status": "approved"}`)
+		reqBody := []byte(`{"id": 123, "status": "approved", "version": 3}`)
 		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
 		req = req.WithContext(context.WithValue(req.Context(), mwr.RequestIDKey, "test"))
 
@@ -274,3 +464,88 @@ func TestHandleUpdateFlat(t *testing.T) {
 		}, response)
 	})
 }
+
+func TestHandleUpdateFlatVersion(t *testing.T) {
+	t.Run("version is required", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "approved"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, errVersionRequired.Error(), response.Error)
+	})
+
+	t.Run("invalid If-Match", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "approved"}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+		req.Header.Set("If-Match", "*")
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, handlers.ErrInvalidETag.Error(), response.Error)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			UpdateFlat(gomock.Any(), int64(123), model.FlatStatusUpdate{Status: model.StatusApproved, Version: 2}, testModerator).
+			Return(nil, flatPkg.ErrFlatModified)
+
+		// Create HTTP request
+		reqBody := []byte(`{"id": 123, "status": "approved", "version": 2}`)
+		req := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, flatPkg.ErrFlatModified.Error(), response.Error)
+	})
+}
@@ -36,16 +36,16 @@ func (m *MockFlatService) EXPECT() *MockFlatServiceMockRecorder {
 }
 
 // UpdateFlat mocks base method.
-func (m *MockFlatService) UpdateFlat(ctx context.Context, ID int64, status model.FlatStatus) (*model.Flat, error) {
+func (m *MockFlatService) UpdateFlat(ctx context.Context, ID int64, update model.FlatStatusUpdate, actor model.Actor) (*model.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFlat", ctx, ID, status)
+	ret := m.ctrl.Call(m, "UpdateFlat", ctx, ID, update, actor)
 	ret0, _ := ret[0].(*model.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateFlat indicates an expected call of UpdateFlat.
-func (mr *MockFlatServiceMockRecorder) UpdateFlat(ctx, ID, status interface{}) *gomock.Call {
+func (mr *MockFlatServiceMockRecorder) UpdateFlat(ctx, ID, update, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlat", reflect.TypeOf((*MockFlatService)(nil).UpdateFlat), ctx, ID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlat", reflect.TypeOf((*MockFlatService)(nil).UpdateFlat), ctx, ID, update, actor)
 }
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	housePkg "avito-backend-bootcamp/internal/service/house"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type HouseService interface {
+	EditHouse(ctx context.Context, ID int64, edit model.HouseEdit) (*model.House, error)
+}
+
+type updateHouseRequest struct {
+	Address   *string `json:"address" validate:"omitempty,min=1"`
+	Year      *int64  `json:"year" validate:"omitempty,gt=0"`
+	Developer *string `json:"developer"`
+}
+
+type updateHouseResponse struct {
+	ID        int64     `json:"id"`
+	Address   string    `json:"address"`
+	Year      int64     `json:"year"`
+	Developer *string   `json:"developer,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var errEmptyEdit = errors.New("nothing to edit: at least one house parameter must be set")
+
+func New(log *slog.Logger, validate *validator.Validate, houseService HouseService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleUpdateHouse"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract house ID from URL parameter
+		houseID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Decode the request body into an updateHouseRequest struct
+		var req updateHouseRequest
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("invalid json", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Validate the request data
+		err = validate.Struct(req)
+		if err != nil {
+			log.Error("input validation failed", sl.Err(err))
+			errors := err.(validator.ValidationErrors)
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(fmt.Errorf("Validation error: %s", errors)))
+			return
+		}
+		edit := model.HouseEdit{
+			Address:   req.Address,
+			Developer: req.Developer,
+			Year:      req.Year,
+		}
+		if edit.Empty() {
+			log.Error("empty edit request")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(errEmptyEdit))
+			return
+		}
+
+		// Update the house
+		house, err := houseService.EditHouse(r.Context(), houseID, edit)
+		if err != nil {
+			log.Error("failed to update house", sl.Err(err))
+			if errors.Is(err, housePkg.ErrHouseNotExist) {
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			if errors.Is(err, housePkg.ErrAddressAlreadyUsed) ||
+				errors.Is(err, housePkg.ErrHouseNearDuplicate) ||
+				errors.Is(err, model.ErrAddressNotFound) {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.NewError(err))
+				return
+			}
+			h.WriteInternalError(r, w, err)
+			return
+		}
+
+		// Return the updated house details
+		log.Info("house updated")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, updateHouseResponse{
+			ID:        house.ID,
+			Address:   house.Address,
+			Year:      house.YearOfConstruction,
+			Developer: dbUtil.FromNullString(house.Developer),
+			CreatedAt: house.CreatedAt,
+			UpdatedAt: house.UpdatedAt,
+		})
+	}
+}
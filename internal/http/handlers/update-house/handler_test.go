@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/update-house/mocks"
+	"avito-backend-bootcamp/internal/model"
+	housePkg "avito-backend-bootcamp/internal/service/house"
+	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(houseService HouseService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Create handler
+	h := New(sl.SetupLogger(), validator.New(), houseService)
+
+	// Mount handler on router
+	r.Patch("/house/{id}", h)
+
+	return r
+}
+
+func TestHandleUpdateHouse(t *testing.T) {
+	address := "new address"
+	year := int64(2020)
+	developer := "some developer"
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+		createdAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+		updatedAt := createdAt.Add(time.Hour)
+		houseService.
+			EXPECT().
+			EditHouse(gomock.Any(), int64(1), model.HouseEdit{Address: &address, Year: &year}).
+			Return(&model.House{
+				ID:                 1,
+				Address:            address,
+				YearOfConstruction: year,
+				Developer:          dbUtil.NewNullString(developer),
+				CreatedAt:          createdAt,
+				UpdatedAt:          updatedAt,
+			}, nil)
+
+		// Create HTTP request
+		reqBody := []byte(`{"address": "new address", "year": 2020}`)
+		req := httptest.NewRequest(http.MethodPatch, "/house/1", bytes.NewReader(reqBody))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response updateHouseResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, updateHouseResponse{
+			ID:        1,
+			Address:   address,
+			Year:      year,
+			Developer: &developer,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		}, response)
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPatch, "/house/1", bytes.NewReader([]byte(`{}`)))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, errEmptyEdit.Error(), response.Error)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock house service
+		houseService := mock.NewMockHouseService(ctrl)
+
+		// Create HTTP request
+		req := httptest.NewRequest(http.MethodPatch, "/house/1", bytes.NewReader([]byte(`{"year": 0}`)))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(houseService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response resp.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Error, "Validation error")
+	})
+
+	errorCases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"house not found", housePkg.ErrHouseNotExist, http.StatusNotFound},
+		{"address already used", housePkg.ErrAddressAlreadyUsed, http.StatusBadRequest},
+		{"near duplicate", housePkg.ErrHouseNearDuplicate, http.StatusBadRequest},
+		{"unknown address", model.ErrAddressNotFound, http.StatusBadRequest},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock house service
+			houseService := mock.NewMockHouseService(ctrl)
+			houseService.
+				EXPECT().
+				EditHouse(gomock.Any(), int64(1), model.HouseEdit{Address: &address}).
+				Return(nil, tc.err)
+
+			// Create HTTP request
+			req := httptest.NewRequest(http.MethodPatch, "/house/1", bytes.NewReader([]byte(`{"address": "new address"}`)))
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(houseService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	h "avito-backend-bootcamp/internal/http/handlers"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	// photoField is the multipart form field carrying the photo.
+	photoField = "photo"
+
+	// multipartOverhead leaves room for the multipart headers around the photo.
+	multipartOverhead = 1 << 20
+)
+
+type FlatService interface {
+	UploadFlatPhoto(ctx context.Context, flatID int64, data []byte, actor model.Actor) (*model.FlatPhoto, error)
+}
+
+func New(log *slog.Logger, flatService FlatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Setup logger
+		const op = "handlers.HandleUploadFlatPhoto"
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		// Extract flat ID from URL parameter
+		flatID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("param parsing failed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.NewError(err))
+			return
+		}
+
+		// Read the photo from the multipart form
+		r.Body = http.MaxBytesReader(w, r.Body, flatPkg.MaxPhotoSize+multipartOverhead)
+		file, _, err := r.FormFile(photoField)
+		if err != nil {
+			log.Error("failed to read photo", sl.Err(err))
+			writeReadError(w, r, err)
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, flatPkg.MaxPhotoSize+1))
+		if err != nil {
+			log.Error("failed to read photo", sl.Err(err))
+			writeReadError(w, r, err)
+			return
+		}
+
+		// Reject oversized photos and unsupported types before touching the flat
+		_, err = flatPkg.CheckPhoto(data)
+		if err != nil {
+			log.Error("invalid photo", sl.Err(err))
+			writePhotoError(w, r, err)
+			return
+		}
+
+		// Upload the photo on behalf of the authenticated owner
+		photo, err := flatService.UploadFlatPhoto(r.Context(), flatID, data, h.ActorFromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to upload photo", sl.Err(err))
+			writePhotoError(w, r, err)
+			return
+		}
+
+		// Return the uploaded photo
+		log.Info("photo uploaded succesfully")
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, photo)
+	}
+}
+
+// writePhotoError maps the errors of the photo upload to the response status.
+func writePhotoError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, flatPkg.ErrFlatNotExist):
+		render.Status(r, http.StatusNotFound)
+	case errors.Is(err, flatPkg.ErrNotFlatOwner),
+		errors.Is(err, model.ErrTransitionForbidden):
+		render.Status(r, http.StatusForbidden)
+	case errors.Is(err, flatPkg.ErrPhotoTooLarge):
+		render.Status(r, http.StatusRequestEntityTooLarge)
+	case errors.Is(err, flatPkg.ErrUnsupportedPhoto):
+		render.Status(r, http.StatusUnsupportedMediaType)
+	case errors.Is(err, model.ErrFlatOnModeration),
+		errors.Is(err, model.ErrFlatClosed),
+		errors.Is(err, flatPkg.ErrFlatModified):
+		render.Status(r, http.StatusConflict)
+	default:
+		h.WriteInternalError(r, w, err)
+		return
+	}
+	render.JSON(w, r, resp.NewError(err))
+}
+
+// writeReadError reports a malformed or oversized upload.
+func writeReadError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		render.Status(r, http.StatusRequestEntityTooLarge)
+		render.JSON(w, r, resp.NewError(flatPkg.ErrPhotoTooLarge))
+		return
+	}
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, resp.NewError(err))
+}
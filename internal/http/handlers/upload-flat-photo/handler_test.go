@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mock "avito-backend-bootcamp/internal/http/handlers/upload-flat-photo/mocks"
+	"avito-backend-bootcamp/internal/model"
+	flatPkg "avito-backend-bootcamp/internal/service/flat"
+	pkgCtx "avito-backend-bootcamp/pkg/utils/ctx"
+	resp "avito-backend-bootcamp/pkg/utils/response"
+	"avito-backend-bootcamp/pkg/utils/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOwner = model.Actor{
+	ID:   uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40"),
+	Role: model.Client,
+}
+
+var testPhoto = []byte("\x89PNG\r\n\x1a\nphoto")
+
+func setupRouter(flatService FlatService) *chi.Mux {
+	// Create router
+	r := chi.NewRouter()
+
+	// Authenticate requests as the test owner
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), pkgCtx.KeyUserID, testOwner.ID)
+			ctx = context.WithValue(ctx, pkgCtx.KeyUserType, testOwner.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	// Create handler
+	h := New(sl.SetupLogger(), flatService)
+
+	// Mount handler on router
+	r.Post("/flat/{id}/photos", h)
+
+	return r
+}
+
+// newUploadRequest builds a multipart request carrying the photo in the given field.
+func newUploadRequest(t *testing.T, field string, photo []byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write(photo)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/flat/1/photos", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestHandleUploadFlatPhoto(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+		flatService.
+			EXPECT().
+			UploadFlatPhoto(gomock.Any(), int64(1), testPhoto, testOwner).
+			Return(&model.FlatPhoto{ID: 5, FlatID: 1, ContentType: "image/png", URL: "/media/a.png", ThumbnailURL: "/media/a_thumb.jpg"}, nil)
+
+		// Create HTTP request
+		req := newUploadRequest(t, photoField, testPhoto)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response model.FlatPhoto
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), response.ID)
+		assert.Equal(t, "/media/a.png", response.URL)
+		assert.Equal(t, "/media/a_thumb.jpg", response.ThumbnailURL)
+	})
+
+	t.Run("missing photo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := newUploadRequest(t, "file", testPhoto)
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Setup mock flat service
+		flatService := mock.NewMockFlatService(ctrl)
+
+		// Create HTTP request
+		req := newUploadRequest(t, photoField, make([]byte, flatPkg.MaxPhotoSize+multipartOverhead))
+
+		// Create HTTP response writer
+		w := httptest.NewRecorder()
+
+		// Execute handler
+		setupRouter(flatService).ServeHTTP(w, req)
+
+		// Assert response
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	rejectCases := []struct {
+		name  string
+		photo []byte
+		err   error
+		code  int
+	}{
+		{"photo over size limit", append(testPhoto, make([]byte, flatPkg.MaxPhotoSize)...), flatPkg.ErrPhotoTooLarge, http.StatusRequestEntityTooLarge},
+		{"plain text", []byte("not a photo at all"), flatPkg.ErrUnsupportedPhoto, http.StatusUnsupportedMediaType},
+		{"gif image", []byte("GIF89a\x01\x00\x01\x00"), flatPkg.ErrUnsupportedPhoto, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range rejectCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service, which must not be reached
+			flatService := mock.NewMockFlatService(ctrl)
+
+			// Create HTTP request
+			req := newUploadRequest(t, photoField, tc.photo)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+
+	errorCases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"flat not found", flatPkg.ErrFlatNotExist, http.StatusNotFound},
+		{"not owner", flatPkg.ErrNotFlatOwner, http.StatusForbidden},
+		{"photo too large", flatPkg.ErrPhotoTooLarge, http.StatusRequestEntityTooLarge},
+		{"unsupported photo", flatPkg.ErrUnsupportedPhoto, http.StatusUnsupportedMediaType},
+		{"flat on moderation", model.ErrFlatOnModeration, http.StatusConflict},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Setup mock flat service
+			flatService := mock.NewMockFlatService(ctrl)
+			flatService.
+				EXPECT().
+				UploadFlatPhoto(gomock.Any(), int64(1), testPhoto, testOwner).
+				Return(nil, tc.err)
+
+			// Create HTTP request
+			req := newUploadRequest(t, photoField, testPhoto)
+
+			// Create HTTP response writer
+			w := httptest.NewRecorder()
+
+			// Execute handler
+			setupRouter(flatService).ServeHTTP(w, req)
+
+			// Assert response
+			assert.Equal(t, tc.code, w.Code)
+
+			var response resp.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+}
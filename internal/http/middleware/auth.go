@@ -5,6 +5,8 @@ import (
 	ctxPkg "avito-backend-bootcamp/pkg/utils/ctx"
 	"context"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 // NewAuthModerator creates a middleware that only allows Moderators
@@ -49,8 +51,22 @@ func authMiddleware(jwtManager JWT, allowedUserTypes []model.UserType) func(next
 				return
 			}
 
-			// Set user type in the context
+			// Get the subject (user ID)
+			subject, err := token.GetSubject()
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			userID, err := uuid.Parse(subject)
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			// Set user type and user ID in the context
 			ctx := context.WithValue(r.Context(), ctxPkg.KeyUserType, userType)
+			ctx = context.WithValue(ctx, ctxPkg.KeyUserID, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -1,17 +1,40 @@
 package server
 
 import (
+	claimFlat "avito-backend-bootcamp/internal/http/handlers/claim-flat"
+	closeFlat "avito-backend-bootcamp/internal/http/handlers/close-flat"
 	createFlat "avito-backend-bootcamp/internal/http/handlers/create-flat"
 	createHouse "avito-backend-bootcamp/internal/http/handlers/create-house"
+	deleteFlatPhoto "avito-backend-bootcamp/internal/http/handlers/delete-flat-photo"
+	deleteHouse "avito-backend-bootcamp/internal/http/handlers/delete-house"
 	dummyLogin "avito-backend-bootcamp/internal/http/handlers/dummy-login"
+	editFlat "avito-backend-bootcamp/internal/http/handlers/edit-flat"
+	flatDecisions "avito-backend-bootcamp/internal/http/handlers/flat-decisions"
+	flatHistory "avito-backend-bootcamp/internal/http/handlers/flat-history"
+	flatPhotoFile "avito-backend-bootcamp/internal/http/handlers/flat-photo-file"
+	flatPhotos "avito-backend-bootcamp/internal/http/handlers/flat-photos"
+	flatPrices "avito-backend-bootcamp/internal/http/handlers/flat-prices"
+	flatTransitions "avito-backend-bootcamp/internal/http/handlers/flat-transitions"
+	getFlat "avito-backend-bootcamp/internal/http/handlers/get-flat"
 	getHouse "avito-backend-bootcamp/internal/http/handlers/get-house"
+	houseStats "avito-backend-bootcamp/internal/http/handlers/house-stats"
+	importFlats "avito-backend-bootcamp/internal/http/handlers/import-flats"
 	login "avito-backend-bootcamp/internal/http/handlers/login"
+	moderateFlats "avito-backend-bootcamp/internal/http/handlers/moderate-flats"
+	moderationQueue "avito-backend-bootcamp/internal/http/handlers/moderation-queue"
+	myFlats "avito-backend-bootcamp/internal/http/handlers/my-flats"
+	recentHouses "avito-backend-bootcamp/internal/http/handlers/recent-houses"
+	searchFlats "avito-backend-bootcamp/internal/http/handlers/search-flats"
+	searchHouses "avito-backend-bootcamp/internal/http/handlers/search-houses"
 	signup "avito-backend-bootcamp/internal/http/handlers/signup"
 	subscribe "avito-backend-bootcamp/internal/http/handlers/subscribe"
 	updateFlat "avito-backend-bootcamp/internal/http/handlers/update-flat"
+	updateHouse "avito-backend-bootcamp/internal/http/handlers/update-house"
+	uploadFlatPhoto "avito-backend-bootcamp/internal/http/handlers/upload-flat-photo"
 
 	"avito-backend-bootcamp/internal/config"
 	mwr "avito-backend-bootcamp/internal/http/middleware"
+	"avito-backend-bootcamp/internal/infra/blob"
 	"avito-backend-bootcamp/internal/infra/jwt"
 	"avito-backend-bootcamp/internal/service/auth"
 	"avito-backend-bootcamp/internal/service/flat"
@@ -40,6 +63,7 @@ func New(
 	houseService *house.Service,
 	subService *sub.Service,
 	jwtManager *jwt.Manager,
+	storage *blob.Local,
 ) (*Server, error) {
 	// init router
 	router := chi.NewRouter()
@@ -58,16 +82,39 @@ func New(
 	// Доступно любому авторизированному
 	router.Group(func(r chi.Router) {
 		r.Use(mwr.NewAuthModeratorOrClient(jwtManager))
-		r.Get("/house/{id}", getHouse.New(log, flatService))
+		r.Get("/house", searchHouses.New(log, validate, houseService))
+		r.Get("/house/recent", recentHouses.New(log, validate, houseService))
+		r.Get("/house/{id}", getHouse.New(log, validate, houseService, flatService))
+		r.Get("/house/{id}/stats", houseStats.New(log, flatService))
 		r.Post("/house/{id}/subscribe", subscribe.New(log, validate, subService))
 		r.Post("/flat/create", createFlat.New(log, validate, flatService))
+		r.Post("/flat/import", importFlats.New(log, validate, flatService))
+		r.Get("/flat/search", searchFlats.New(log, validate, flatService))
+		r.Get("/flat/{id}", getFlat.New(log, flatService))
+		r.Get("/flat/{id}/prices", flatPrices.New(log, flatService))
+		r.Get("/flat/{id}/photos", flatPhotos.New(log, flatService))
+		// photo files are stored under flats/{id}/ keys
+		r.Get(cfg.Storage.BaseURL+"/flats/{id}/*", flatPhotoFile.New(log, flatService, http.StripPrefix(cfg.Storage.BaseURL, storage.Handler())))
+		r.Post("/flat/{id}/photos", uploadFlatPhoto.New(log, flatService))
+		r.Delete("/flat/{id}/photos/{photoID}", deleteFlatPhoto.New(log, flatService))
+		r.Patch("/flat/{id}", editFlat.New(log, validate, flatService))
+		r.Post("/flat/{id}/close", closeFlat.New(log, validate, flatService))
+		r.Post("/flat/update", updateFlat.New(log, validate, flatService))
+		r.Get("/flat/{id}/transitions", flatTransitions.New(log, flatService))
+		r.Get("/flat/{id}/history", flatHistory.New(log, flatService))
+		r.Get("/flat/{id}/decisions", flatDecisions.New(log, validate, flatService))
+		r.Get("/me/flats", myFlats.New(log, validate, flatService))
 	})
 
 	// Доступно только для модераторов
 	router.Group(func(r chi.Router) {
 		r.Use(mwr.NewAuthModerator(jwtManager))
 		r.Post("/house/create", createHouse.New(log, validate, houseService))
-		r.Post("/flat/update", updateFlat.New(log, validate, flatService))
+		r.Patch("/house/{id}", updateHouse.New(log, validate, houseService))
+		r.Delete("/house/{id}", deleteHouse.New(log, houseService))
+		r.Post("/flat/moderate", moderateFlats.New(log, validate, flatService))
+		r.Get("/moderation/queue", moderationQueue.New(log, validate, flatService))
+		r.Post("/moderation/claim", claimFlat.New(log, flatService))
 	})
 
 	return &Server{
@@ -0,0 +1,20 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Storage stores binary objects by key and serves them by URL.
+// Implementations may keep objects on the local disk or in an S3-compatible storage.
+type Storage interface {
+	// Put stores the object under the key, replacing an existing one.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address the object can be downloaded from.
+	URL(key string) string
+}
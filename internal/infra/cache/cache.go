@@ -85,3 +85,16 @@ func (c *TTLCache[K, V]) Remove(key K) {
 	// Delete the item with the given key from the cache.
 	delete(c.items, key)
 }
+
+// RemoveFunc removes all items whose keys satisfy the given predicate.
+func (c *TTLCache[K, V]) RemoveFunc(fn func(key K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Delete every item with a matching key from the cache.
+	for key := range c.items {
+		if fn(key) {
+			delete(c.items, key)
+		}
+	}
+}
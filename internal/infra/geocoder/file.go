@@ -0,0 +1,68 @@
+package geocoder
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// File geocodes addresses against a fixed list of known addresses read from
+// a YAML file. It stands in for an external geocoding service.
+type File struct {
+	addresses map[string]model.GeoAddress
+}
+
+// Address is a known address with its coordinates.
+type Address struct {
+	Address string         `yaml:"address"`
+	Point   model.GeoPoint `yaml:",inline"`
+}
+
+type addressList struct {
+	Addresses []Address `yaml:"addresses"`
+}
+
+// LoadFile reads the known addresses from the YAML file.
+func LoadFile(path string) (*File, error) {
+	var list addressList
+	if err := cleanenv.ReadConfig(path, &list); err != nil {
+		return nil, fmt.Errorf("cannot read geocoder addresses: %w", err)
+	}
+
+	return NewFile(list.Addresses), nil
+}
+
+func NewFile(addresses []Address) *File {
+	known := make(map[string]model.GeoAddress, len(addresses))
+	for _, address := range addresses {
+		point := address.Point
+		known[model.NormalizeAddress(address.Address)] = model.GeoAddress{
+			Address: address.Address,
+			Point:   &point,
+		}
+	}
+
+	return &File{addresses: known}
+}
+
+// Geocode returns the known address matching the given one
+// in its canonical spelling along with its coordinates.
+func (f *File) Geocode(ctx context.Context, address string) (*model.GeoAddress, error) {
+	known, ok := f.addresses[model.NormalizeAddress(address)]
+	if !ok {
+		return nil, model.ErrAddressNotFound
+	}
+
+	return &known, nil
+}
+
+// Passthrough keeps addresses as they are, without coordinates.
+// It is used while no external geocoding service is connected.
+type Passthrough struct{}
+
+func (Passthrough) Geocode(ctx context.Context, address string) (*model.GeoAddress, error) {
+	return &model.GeoAddress{Address: strings.Join(strings.Fields(address), " ")}, nil
+}
@@ -0,0 +1,40 @@
+package geocoder
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"avito-backend-bootcamp/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	geocoder, err := LoadFile(filepath.Join("testdata", "addresses.yaml"))
+	require.NoError(t, err)
+
+	t.Run("known address", func(t *testing.T) {
+		address, err := geocoder.Geocode(context.Background(), "Ленина ул 1")
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.GeoAddress{
+			Address: "ул. Ленина, д. 1",
+			Point:   &model.GeoPoint{Latitude: 55.751244, Longitude: 37.618423},
+		}, address)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		_, err := geocoder.Geocode(context.Background(), "ул. Ленина, д. 2")
+
+		assert.ErrorIs(t, err, model.ErrAddressNotFound)
+	})
+}
+
+func TestPassthrough(t *testing.T) {
+	address, err := Passthrough{}.Geocode(context.Background(), "  ул.  Ленина, 1 ")
+
+	require.NoError(t, err)
+	assert.Equal(t, &model.GeoAddress{Address: "ул. Ленина, 1"}, address)
+}
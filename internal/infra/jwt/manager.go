@@ -20,8 +20,9 @@ func New(secretKey string, tokenTTL time.Duration) *Manager {
 }
 
 // CreateToken creates JWT tokens with claims
-func (m Manager) CreateToken(role string) (string, error) {
+func (m Manager) CreateToken(userID, role string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID,
 		"aud": role,
 		"exp": time.Now().Add(m.tokenTTL).Unix(),
 	})
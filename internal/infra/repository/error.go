@@ -6,4 +6,5 @@ var (
 	ErrNotFound            = errors.New("entity not found")
 	ErrConstraintViolation = errors.New("db constraint violation")
 	ErrAlreadyExists       = errors.New("unique constraint violation")
+	ErrVersionConflict     = errors.New("entity version conflict")
 )
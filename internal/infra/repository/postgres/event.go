@@ -51,3 +51,19 @@ func (r *Repository) SetDone(ctx context.Context, eventID int64) error {
 
 	return nil
 }
+
+// DiscardHouseEvents marks the unprocessed events of the house as processed
+// and returns the number of discarded events.
+func (r *Repository) DiscardHouseEvents(ctx context.Context, houseID int64) (int64, error) {
+	// Update the pending events referring to the house in their payload
+	result, err := r.getter.DefaultTrOrDB(ctx, r.db).ExecContext(ctx,
+		`UPDATE events
+		SET processed_at = NOW()
+		WHERE processed_at IS NULL AND (payload::jsonb ->> 'house_id')::bigint = $1`,
+		houseID)
+	if err != nil {
+		return 0, PostgresErrorTransform(err)
+	}
+
+	return result.RowsAffected()
+}
@@ -1,8 +1,16 @@
 package postgres
 
 import (
+	repo "avito-backend-bootcamp/internal/infra/repository"
 	"avito-backend-bootcamp/internal/model"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // GetFlat retrieves a flat by its ID from the database.
@@ -23,15 +31,19 @@ func (r *Repository) GetFlat(ctx context.Context, ID int64) (*model.Flat, error)
 }
 
 // SaveFlat saves a new flat to the database.
-func (r *Repository) SaveFlat(ctx context.Context, houseID, price, rooms int64) (*model.Flat, error) {
+func (r *Repository) SaveFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error) {
 	query :=
-		"INSERT INTO flats (house_id, price, rooms) " +
-			"VALUES ($1, $2, $3) " +
+		"INSERT INTO flats (house_id, number, price, rooms, owner_id, " +
+			"total_area, kitchen_area, floor, description, layout) " +
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) " +
 			"RETURNING *"
 
 	var flat model.Flat
 	err := r.getter.DefaultTrOrDB(ctx, r.db).
-		GetContext(ctx, &flat, query, houseID, price, rooms)
+		GetContext(ctx, &flat, query,
+			newFlat.HouseID, newFlat.Number, newFlat.Price, newFlat.Rooms, newFlat.OwnerID,
+			newFlat.TotalArea, newFlat.KitchenArea, newFlat.Floor, newFlat.Description, newFlat.Layout,
+		)
 	if err != nil {
 		return nil, PostgresErrorTransform(err)
 	}
@@ -39,36 +51,251 @@ func (r *Repository) SaveFlat(ctx context.Context, houseID, price, rooms int64)
 	return &flat, nil
 }
 
-// UpdateFlat updates an existing flat in the database.
+// UpdateFlat updates an existing flat in the database if its version
+// has not changed since it was read, and increments the version.
+// The moderation start time is kept while the flat stays on moderation.
 func (r *Repository) UpdateFlat(ctx context.Context, flat *model.Flat) (*model.Flat, error) {
 	query :=
 		"UPDATE flats " +
-			"SET house_id = $1, price = $2, rooms = $3, status = $4 " +
-			"WHERE id = $5 " +
+			"SET house_id = $1, price = $2, rooms = $3, status = $4, moderator_id = $5, " +
+			"decline_reason = $6, decline_comment = $7, previous_price = $8, " +
+			"total_area = $9, kitchen_area = $10, floor = $11, description = $12, layout = $13, " +
+			"flagged = $14, version = version + 1, " +
+			"moderation_started_at = CASE WHEN $4::moderation_status = 'on_moderation' " +
+			"THEN COALESCE(moderation_started_at, NOW()) END " +
+			"WHERE id = $15 AND version = $16 " +
 			"RETURNING *"
 
 	err := r.getter.DefaultTrOrDB(ctx, r.db).
-		GetContext(ctx, flat, query, flat.HouseID, flat.Price, flat.Rooms, flat.Status, flat.ID)
+		GetContext(ctx, flat, query,
+			flat.HouseID, flat.Price, flat.Rooms, flat.Status, flat.ModeratorID,
+			flat.DeclineReason, flat.DeclineComment, flat.PreviousPrice,
+			flat.TotalArea, flat.KitchenArea, flat.Floor, flat.Description, flat.Layout,
+			flat.Flagged, flat.ID, flat.Version,
+		)
 	if err != nil {
-		return nil, PostgresErrorTransform(err)
+		err = PostgresErrorTransform(err)
+		// the flat was read before the update, so a missing row
+		// means it has been changed concurrently
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, repo.ErrVersionConflict
+		}
+		return nil, err
 	}
 
 	return flat, nil
 }
 
-// FlatListByHouseID retrieves a list of flats associated with a given house ID.
-func (r *Repository) FlatListByHouseID(ctx context.Context, houseID int64) ([]*model.Flat, error) {
+// FlatModerationQueue retrieves flats waiting for moderation, oldest first.
+func (r *Repository) FlatModerationQueue(ctx context.Context, limit int64) ([]*model.Flat, error) {
+	query :=
+		"SELECT * " +
+			"FROM flats " +
+			"WHERE status = $1 " +
+			"ORDER BY created_at, id " +
+			"LIMIT $2"
+
+	var flats []*model.Flat
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &flats, query, model.StatusCreated, limit)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return flats, nil
+}
+
+// GetNextFlatForModeration locks and retrieves the oldest flat waiting for moderation.
+// Flats locked by concurrent transactions are skipped, so it must be called
+// inside a transaction that changes the flat status.
+func (r *Repository) GetNextFlatForModeration(ctx context.Context) (*model.Flat, error) {
+	query :=
+		"SELECT * " +
+			"FROM flats " +
+			"WHERE status = $1 " +
+			"ORDER BY created_at, id " +
+			"LIMIT 1 " +
+			"FOR UPDATE SKIP LOCKED"
+
+	var flat model.Flat
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, &flat, query, model.StatusCreated)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return &flat, nil
+}
+
+// StaleModerationFlats locks and retrieves flats that have been on moderation
+// since before the given time, longest first. Flats locked by concurrent
+// transactions are skipped, so it must be called inside a transaction
+// that changes the flat status.
+func (r *Repository) StaleModerationFlats(ctx context.Context, startedBefore time.Time, limit int64) ([]*model.Flat, error) {
 	query :=
 		"SELECT * " +
 			"FROM flats " +
-			"WHERE house_id = $1"
+			"WHERE status = $1 AND moderation_started_at < $2 " +
+			"ORDER BY moderation_started_at, id " +
+			"LIMIT $3 " +
+			"FOR UPDATE SKIP LOCKED"
 
 	var flats []*model.Flat
 	err := r.getter.DefaultTrOrDB(ctx, r.db).
-		SelectContext(ctx, &flats, query, houseID)
+		SelectContext(ctx, &flats, query, model.StatusOnModeration, startedBefore, limit)
 	if err != nil {
 		return nil, PostgresErrorTransform(err)
 	}
 
 	return flats, nil
 }
+
+// SearchFlats retrieves flats across all houses matching the given filter,
+// ordered by the filter sort and starting right after the filter cursor.
+func (r *Repository) SearchFlats(ctx context.Context, filter model.FlatFilter) ([]*model.Flat, error) {
+	where, args := flatFilterConditions(filter)
+
+	column := "f." + filter.Sort.Column()
+	direction, comparison := "ASC", ">"
+	if filter.Sort.Desc() {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Value, filter.After.ID)
+		where = append(where, fmt.Sprintf("(%s, f.id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+
+	query :=
+		"SELECT f.* " +
+			"FROM flats f " +
+			"JOIN houses h ON h.id = f.house_id "
+	if len(where) > 0 {
+		query += "WHERE " + strings.Join(where, " AND ") + " "
+	}
+	query += fmt.Sprintf("ORDER BY %s %s, f.id %s ", column, direction, direction)
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	var flats []*model.Flat
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &flats, query, args...)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return flats, nil
+}
+
+// CountFlats counts flats matching the given filter ignoring its cursor and limit.
+func (r *Repository) CountFlats(ctx context.Context, filter model.FlatFilter) (int64, error) {
+	where, args := flatFilterConditions(filter)
+
+	query :=
+		"SELECT COUNT(*) " +
+			"FROM flats f " +
+			"JOIN houses h ON h.id = f.house_id "
+	if len(where) > 0 {
+		query += "WHERE " + strings.Join(where, " AND ")
+	}
+
+	var count int64
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, &count, query, args...)
+	if err != nil {
+		return 0, PostgresErrorTransform(err)
+	}
+
+	return count, nil
+}
+
+// FindDuplicateFlats retrieves IDs of other flats of the same house still in the
+// catalogue that have the same rooms, price, floor and total area as the flat.
+func (r *Repository) FindDuplicateFlats(ctx context.Context, flat *model.Flat) ([]int64, error) {
+	query :=
+		"SELECT id " +
+			"FROM flats " +
+			"WHERE house_id = $1 AND id <> $2 AND rooms = $3 AND price = $4 " +
+			"AND floor IS NOT DISTINCT FROM $5 AND total_area IS NOT DISTINCT FROM $6 " +
+			"AND status NOT IN ('archived', 'sold') " +
+			"ORDER BY id"
+
+	var ids []int64
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &ids, query, flat.HouseID, flat.ID, flat.Rooms, flat.Price, flat.Floor, flat.TotalArea)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return ids, nil
+}
+
+// flatFilterConditions builds WHERE conditions and their arguments for the filter.
+// Flats are expected to be aliased as f and joined houses as h.
+func flatFilterConditions(filter model.FlatFilter) ([]string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(filter.HouseIDs) > 0 {
+		add("f.house_id = ANY($%d)", pq.Array(filter.HouseIDs))
+	}
+	if len(filter.Rooms) > 0 {
+		add("f.rooms = ANY($%d)", pq.Array(filter.Rooms))
+	}
+	if filter.PriceMin > 0 {
+		add("f.price >= $%d", filter.PriceMin)
+	}
+	if filter.PriceMax > 0 {
+		add("f.price <= $%d", filter.PriceMax)
+	}
+	if filter.AreaMin > 0 {
+		add("f.total_area >= $%d", filter.AreaMin)
+	}
+	if filter.AreaMax > 0 {
+		add("f.total_area <= $%d", filter.AreaMax)
+	}
+	if filter.FloorMin > 0 {
+		add("f.floor >= $%d", filter.FloorMin)
+	}
+	if filter.FloorMax > 0 {
+		add("f.floor <= $%d", filter.FloorMax)
+	}
+	if len(filter.Layouts) > 0 {
+		layouts := make([]string, 0, len(filter.Layouts))
+		for _, layout := range filter.Layouts {
+			layouts = append(layouts, string(layout))
+		}
+		add("f.layout = ANY($%d::flat_layout[])", pq.Array(layouts))
+	}
+	if filter.Developer != "" {
+		add("h.developer = $%d", filter.Developer)
+	}
+	if filter.YearMin > 0 {
+		add("h.year_of_construction >= $%d", filter.YearMin)
+	}
+	if filter.YearMax > 0 {
+		add("h.year_of_construction <= $%d", filter.YearMax)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, st := range filter.Statuses {
+			statuses = append(statuses, string(st))
+		}
+		add("f.status = ANY($%d::moderation_status[])", pq.Array(statuses))
+	}
+	if filter.OwnerID != uuid.Nil {
+		add("f.owner_id = $%d", filter.OwnerID)
+	}
+
+	return where, args
+}
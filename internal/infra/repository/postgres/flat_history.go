@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+)
+
+// SaveFlatStatusChange appends a status change to the flat history.
+func (r *Repository) SaveFlatStatusChange(ctx context.Context, change *model.FlatStatusChange) error {
+	query :=
+		"INSERT INTO flat_status_history (flat_id, old_status, new_status, actor_id, actor_role, reason) " +
+			"VALUES ($1, $2, $3, $4, $5, $6)"
+
+	_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, change.FlatID, change.OldStatus, change.NewStatus, change.ActorID, change.ActorRole, change.Reason)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	return nil
+}
+
+// FlatStatusHistory retrieves status changes of the flat in chronological order.
+func (r *Repository) FlatStatusHistory(ctx context.Context, flatID int64) ([]*model.FlatStatusChange, error) {
+	query :=
+		"SELECT * " +
+			"FROM flat_status_history " +
+			"WHERE flat_id = $1 " +
+			"ORDER BY created_at, id"
+
+	var history []*model.FlatStatusChange
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &history, query, flatID)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return history, nil
+}
+
+// SaveFlatPriceChange appends the current price of the flat to its price history.
+func (r *Repository) SaveFlatPriceChange(ctx context.Context, flatID, price int64) error {
+	query :=
+		"INSERT INTO flat_price_history (flat_id, price) " +
+			"VALUES ($1, $2)"
+
+	_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, flatID, price)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	return nil
+}
+
+// FlatPriceHistory retrieves price changes of the flat in chronological order.
+func (r *Repository) FlatPriceHistory(ctx context.Context, flatID int64) ([]*model.FlatPriceChange, error) {
+	query :=
+		"SELECT * " +
+			"FROM flat_price_history " +
+			"WHERE flat_id = $1 " +
+			"ORDER BY created_at, id"
+
+	var history []*model.FlatPriceChange
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &history, query, flatID)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return history, nil
+}
+
+// SaveFlatRuleDecisions appends premoderation decisions to the flat history.
+func (r *Repository) SaveFlatRuleDecisions(ctx context.Context, decisions []*model.RuleDecision) error {
+	query :=
+		"INSERT INTO flat_rule_decisions (flat_id, rule, action, reason, details) " +
+			"VALUES ($1, $2, $3, $4, $5)"
+
+	for _, decision := range decisions {
+		_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+			ExecContext(ctx, query, decision.FlatID, decision.Rule, decision.Action, decision.Reason, decision.Details)
+		if err != nil {
+			return PostgresErrorTransform(err)
+		}
+	}
+
+	return nil
+}
+
+// FlatRuleDecisions retrieves a page of premoderation decisions on the flat
+// in chronological order, starting after the cursor of the filter.
+func (r *Repository) FlatRuleDecisions(ctx context.Context, flatID int64, filter model.RuleDecisionFilter) ([]*model.RuleDecision, error) {
+	var after int64
+	if filter.After != nil {
+		after = filter.After.ID
+	}
+
+	query :=
+		"SELECT * " +
+			"FROM flat_rule_decisions " +
+			"WHERE flat_id = $1 AND id > $2 " +
+			"ORDER BY id " +
+			"LIMIT $3"
+
+	var decisions []*model.RuleDecision
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &decisions, query, flatID, after, filter.Limit)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return decisions, nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+
+	"github.com/lib/pq"
+)
+
+// SaveFlatPhoto saves a new photo of the flat to the database.
+func (r *Repository) SaveFlatPhoto(ctx context.Context, photo *model.FlatPhoto) (*model.FlatPhoto, error) {
+	query :=
+		"INSERT INTO flat_photos (flat_id, key, thumbnail_key, content_type, size) " +
+			"VALUES ($1, $2, $3, $4, $5) " +
+			"RETURNING *"
+
+	var saved model.FlatPhoto
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, &saved, query, photo.FlatID, photo.Key, photo.ThumbnailKey, photo.ContentType, photo.Size)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return &saved, nil
+}
+
+// GetFlatPhoto retrieves a photo by its ID from the database.
+func (r *Repository) GetFlatPhoto(ctx context.Context, ID int64) (*model.FlatPhoto, error) {
+	query :=
+		"SELECT * " +
+			"FROM flat_photos " +
+			"WHERE id = $1"
+
+	var photo model.FlatPhoto
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, &photo, query, ID)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return &photo, nil
+}
+
+// FlatPhotos retrieves photos of the given flats in upload order.
+func (r *Repository) FlatPhotos(ctx context.Context, flatIDs []int64) ([]*model.FlatPhoto, error) {
+	query :=
+		"SELECT * " +
+			"FROM flat_photos " +
+			"WHERE flat_id = ANY($1) " +
+			"ORDER BY created_at, id"
+
+	var photos []*model.FlatPhoto
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &photos, query, pq.Array(flatIDs))
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return photos, nil
+}
+
+// HouseFlatPhotos retrieves photos of all flats of the house.
+func (r *Repository) HouseFlatPhotos(ctx context.Context, houseID int64) ([]*model.FlatPhoto, error) {
+	query :=
+		"SELECT p.* " +
+			"FROM flat_photos p " +
+			"JOIN flats f ON f.id = p.flat_id " +
+			"WHERE f.house_id = $1 " +
+			"ORDER BY p.id"
+
+	var photos []*model.FlatPhoto
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &photos, query, houseID)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return photos, nil
+}
+
+// DeleteFlatPhoto removes the photo from the database.
+func (r *Repository) DeleteFlatPhoto(ctx context.Context, ID int64) error {
+	query :=
+		"DELETE FROM flat_photos " +
+			"WHERE id = $1"
+
+	_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, ID)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	return nil
+}
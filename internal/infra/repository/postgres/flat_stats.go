@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+)
+
+// FlatPriceStats computes price statistics of approved flats of the house
+// grouped by the number of rooms, ordered by rooms.
+func (r *Repository) FlatPriceStats(ctx context.Context, houseID int64) ([]*model.RoomsPriceStats, error) {
+	query :=
+		"SELECT rooms, COUNT(*) AS count, MIN(price) AS min_price, MAX(price) AS max_price, " +
+			"ROUND(AVG(price), 2)::FLOAT8 AS avg_price, " +
+			"PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY price) AS median_price " +
+			"FROM flats " +
+			"WHERE house_id = $1 AND status = $2 " +
+			"GROUP BY rooms " +
+			"ORDER BY rooms"
+
+	var stats []*model.RoomsPriceStats
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &stats, query, houseID, model.StatusApproved)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return stats, nil
+}
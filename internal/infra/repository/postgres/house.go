@@ -1,37 +1,40 @@
 package postgres
 
 import (
+	repo "avito-backend-bootcamp/internal/infra/repository"
 	"avito-backend-bootcamp/internal/model"
 	dbUtil "avito-backend-bootcamp/pkg/utils/db"
+	"fmt"
+	"strings"
+	"time"
 
 	"context"
-	"database/sql"
 )
 
 // SaveHouse saves a new house to the database.
-func (r *Repository) SaveHouse(ctx context.Context, address, developer string, year int64) (*model.House, error) {
+func (r *Repository) SaveHouse(ctx context.Context, newHouse model.NewHouse) (*model.House, error) {
 	// Prepare the query to insert the house
 	query :=
-		"INSERT INTO houses (address, developer, year_of_construction) " +
-			"VALUES ($1, $2, $3) RETURNING id"
+		"INSERT INTO houses (address, address_key, developer, year_of_construction, latitude, longitude) " +
+			"VALUES ($1, $2, $3, $4, $5, $6) RETURNING *"
+
+	var latitude, longitude *float64
+	if newHouse.Point != nil {
+		latitude, longitude = &newHouse.Point.Latitude, &newHouse.Point.Longitude
+	}
 
 	// Insert the house using the prepared query
-	var houseID int64
+	var house model.House
 	err := r.getter.DefaultTrOrDB(ctx, r.db).
-		GetContext(ctx, &houseID, query, address, dbUtil.NewNullString(developer), year)
+		GetContext(ctx, &house, query,
+			newHouse.Address, newHouse.AddressKey, dbUtil.NewNullString(newHouse.Developer), newHouse.Year,
+			latitude, longitude,
+		)
 	if err != nil {
 		return nil, PostgresErrorTransform(err)
 	}
 
-	// Create a new house object with the inserted ID
-	house := &model.House{
-		ID:                 houseID,
-		Address:            address,
-		Developer:          sql.NullString{},
-		YearOfConstruction: year,
-	}
-
-	return house, nil
+	return &house, nil
 }
 
 // GetHouse retrieves a house by its ID from the database.
@@ -52,3 +55,205 @@ func (r *Repository) GetHouse(ctx context.Context, id int64) (*model.House, erro
 
 	return &house, nil
 }
+
+// GetHouseSummary retrieves a house by its ID from the database
+// along with the number of its approved flats and subscribers.
+func (r *Repository) GetHouseSummary(ctx context.Context, id int64) (*model.HouseSummary, error) {
+	query :=
+		"SELECT h.*, " +
+			"(SELECT COUNT(*) FROM flats f WHERE f.house_id = h.id AND f.status = $2) AS approved_flats, " +
+			"(SELECT COUNT(*) FROM subscriptions s WHERE s.house_id = h.id) AS subscribers " +
+			"FROM houses h " +
+			"WHERE h.id = $1"
+
+	var house model.HouseSummary
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, &house, query, id, model.StatusApproved)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return &house, nil
+}
+
+// houseLocationsLock is the key of the advisory lock serializing
+// the changes of house locations.
+const houseLocationsLock = 7312001
+
+// LockHouseLocations takes the transaction-level lock on the house locations,
+// so that concurrent checks for near-duplicates see each other's houses.
+func (r *Repository) LockHouseLocations(ctx context.Context) error {
+	_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", houseLocationsLock)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	return nil
+}
+
+// HousesWithin retrieves the houses located within the given bounds.
+func (r *Repository) HousesWithin(ctx context.Context, lower, upper model.GeoPoint) ([]*model.House, error) {
+	query :=
+		"SELECT * " +
+			"FROM houses " +
+			"WHERE latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4 " +
+			"ORDER BY id"
+
+	var houses []*model.House
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &houses, query, lower.Latitude, upper.Latitude, lower.Longitude, upper.Longitude)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return houses, nil
+}
+
+// UpdateHouse updates an existing house in the database
+// and moves its update time forward.
+func (r *Repository) UpdateHouse(ctx context.Context, house *model.House) (*model.House, error) {
+	query :=
+		"UPDATE houses " +
+			"SET address = $1, address_key = $2, developer = $3, year_of_construction = $4, " +
+			"latitude = $5, longitude = $6, updated_at = NOW() " +
+			"WHERE id = $7 " +
+			"RETURNING *"
+
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		GetContext(ctx, house, query,
+			house.Address, house.AddressKey, house.Developer, house.YearOfConstruction,
+			house.Latitude, house.Longitude, house.ID,
+		)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return house, nil
+}
+
+// DeleteHouse removes a house from the database.
+// Flats and subscriptions of the house must be removed beforehand.
+func (r *Repository) DeleteHouse(ctx context.Context, id int64) error {
+	query :=
+		"DELETE FROM houses " +
+			"WHERE id = $1"
+
+	result, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, id)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteHouseFlats removes all flats of the house along with their history,
+// photos and rule decisions, and returns the number of removed flats.
+func (r *Repository) DeleteHouseFlats(ctx context.Context, houseID int64) (int64, error) {
+	query :=
+		"DELETE FROM flats " +
+			"WHERE house_id = $1"
+
+	result, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, houseID)
+	if err != nil {
+		return 0, PostgresErrorTransform(err)
+	}
+
+	return result.RowsAffected()
+}
+
+// MarkHouseFlatPublished records that a flat of the house has just been published.
+func (r *Repository) MarkHouseFlatPublished(ctx context.Context, houseID int64) error {
+	query :=
+		"UPDATE houses " +
+			"SET updated_at = NOW(), last_flat_published_at = NOW() " +
+			"WHERE id = $1"
+
+	_, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, houseID)
+	if err != nil {
+		return PostgresErrorTransform(err)
+	}
+
+	return nil
+}
+
+// SearchHouses retrieves houses matching the given filter, ordered by the time
+// of the last update or flat publication and starting right after the filter cursor.
+// Houses without published flats are left out when ordered by publication.
+func (r *Repository) SearchHouses(ctx context.Context, filter model.HouseFilter) ([]*model.House, error) {
+	var (
+		where []string
+		args  []any
+	)
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Developer != "" {
+		add("developer = $%d", filter.Developer)
+	}
+	if filter.YearMin > 0 {
+		add("year_of_construction >= $%d", filter.YearMin)
+	}
+	if filter.YearMax > 0 {
+		add("year_of_construction <= $%d", filter.YearMax)
+	}
+	if filter.Address != "" {
+		add("address ILIKE $%d", "%"+escapeLike(filter.Address)+"%")
+	}
+
+	column := filter.Sort.Column()
+	if column == "last_flat_published_at" {
+		where = append(where, "last_flat_published_at IS NOT NULL")
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Sort.Desc() {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		args = append(args, time.UnixMicro(filter.After.Value).UTC(), filter.After.ID)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+
+	query :=
+		"SELECT * " +
+			"FROM houses "
+	if len(where) > 0 {
+		query += "WHERE " + strings.Join(where, " AND ") + " "
+	}
+	query += fmt.Sprintf("ORDER BY %s %s, id %s ", column, direction, direction)
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	var houses []*model.House
+	err := r.getter.DefaultTrOrDB(ctx, r.db).
+		SelectContext(ctx, &houses, query, args...)
+	if err != nil {
+		return nil, PostgresErrorTransform(err)
+	}
+
+	return houses, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(str string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(str)
+}
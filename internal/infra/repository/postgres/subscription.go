@@ -40,3 +40,19 @@ func (r *Repository) SaveSubscritpion(ctx context.Context, houseID int64, email
 
 	return nil
 }
+
+// DeleteHouseSubscriptions removes all subscriptions to the house
+// and returns the number of removed subscriptions.
+func (r *Repository) DeleteHouseSubscriptions(ctx context.Context, houseID int64) (int64, error) {
+	query :=
+		"DELETE FROM subscriptions " +
+			"WHERE house_id = $1"
+
+	result, err := r.getter.DefaultTrOrDB(ctx, r.db).
+		ExecContext(ctx, query, houseID)
+	if err != nil {
+		return 0, PostgresErrorTransform(err)
+	}
+
+	return result.RowsAffected()
+}
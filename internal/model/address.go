@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// addressNoise lists the words that do not tell addresses apart:
+// abbreviations of street and building types.
+var addressNoise = map[string]struct{}{
+	"г":      {},
+	"город":  {},
+	"ул":     {},
+	"улица":  {},
+	"д":      {},
+	"дом":    {},
+	"street": {},
+	"st":     {},
+}
+
+// addressSynonyms maps the full names of building parts to their abbreviations.
+var addressSynonyms = map[string]string{
+	"корпус":   "корп",
+	"строение": "стр",
+}
+
+// NormalizeAddress reduces the free-form address to a key that is equal
+// for spellings of the same address differing in case, punctuation
+// and street type abbreviations. The order of the remaining words is kept,
+// so the numbers of the building and its parts are not mixed up.
+func NormalizeAddress(address string) string {
+	words := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	key := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ReplaceAll(word, "ё", "е")
+		if _, ok := addressNoise[word]; ok {
+			continue
+		}
+		if synonym, ok := addressSynonyms[word]; ok {
+			word = synonym
+		}
+		key = append(key, word)
+	}
+
+	return strings.Join(key, " ")
+}
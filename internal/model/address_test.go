@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	cases := []struct {
+		name  string
+		left  string
+		right string
+	}{
+		{"street type position", "ул. Ленина, 1", "Ленина ул 1"},
+		{"full street type and building", "улица Ленина, дом 1", "ул. Ленина, д. 1"},
+		{"case and spaces", "  ТВЕРСКАЯ   7 ", "тверская 7"},
+		{"yo", "ул. Зелёная, 5", "Зеленая ул 5"},
+		{"building part", "ул. Ленина, д. 12, корпус 3", "Ленина 12 корп. 3"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, NormalizeAddress(tc.left), NormalizeAddress(tc.right))
+		})
+	}
+
+	assert.NotEqual(t, NormalizeAddress("ул. Ленина, 1"), NormalizeAddress("ул. Ленина, 11"))
+	assert.NotEqual(t, NormalizeAddress("ул. Ленина, д. 12, корп. 3"), NormalizeAddress("ул. Ленина, д. 3, корп. 12"))
+}
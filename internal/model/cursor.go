@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Курсор для постраничной выборки.
+// Value - значение поля сортировки последней записи страницы, ID - её идентификатор.
+type Cursor struct {
+	Value int64 `json:"v"`
+	ID    int64 `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns an opaque string representation of the cursor.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseCursor decodes a cursor previously produced by Encode.
+func ParseCursor(str string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
@@ -15,6 +15,8 @@ const (
 	StatusApproved     FlatStatus = "approved"
 	StatusDeclined     FlatStatus = "declined"
 	StatusOnModeration FlatStatus = "on_moderation"
+	StatusArchived     FlatStatus = "archived"
+	StatusSold         FlatStatus = "sold"
 )
 
 func ParseFlatStatus(str string) (FlatStatus, error) {
@@ -29,6 +31,10 @@ func ParseFlatStatus(str string) (FlatStatus, error) {
 		st = StatusDeclined
 	case string(StatusOnModeration):
 		st = StatusOnModeration
+	case string(StatusArchived):
+		st = StatusArchived
+	case string(StatusSold):
+		st = StatusSold
 	default:
 		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
 	}
@@ -62,6 +68,8 @@ type UserType string
 const (
 	Moderator UserType = "moderator"
 	Client    UserType = "client"
+	// System is the role of automatic actions, it is never assigned to users.
+	System UserType = "system"
 )
 
 func MustParseUserType(str string) UserType {
@@ -93,6 +101,11 @@ func (ut *UserType) Scan(value interface{}) error {
 		return errors.New("faile type assertion")
 	}
 
+	if UserType(str) == System {
+		*ut = System
+		return nil
+	}
+
 	status, err := ParseUserType(string(str))
 	if err != nil {
 		return err
@@ -112,6 +125,8 @@ type EventType string
 
 const (
 	FlatApproved EventType = "flat_approved"
+	FlatArchived EventType = "flat_archived"
+	FlatSold     EventType = "flat_sold"
 )
 
 func ParseEventType(str string) (EventType, error) {
@@ -120,6 +135,10 @@ func ParseEventType(str string) (EventType, error) {
 	switch str {
 	case string(FlatApproved):
 		et = FlatApproved
+	case string(FlatArchived):
+		et = FlatArchived
+	case string(FlatSold):
+		et = FlatSold
 	default:
 		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
 	}
@@ -145,3 +164,278 @@ func (ut *EventType) Scan(value interface{}) error {
 func (ut EventType) Value() (driver.Value, error) {
 	return string(ut), nil
 }
+
+//======|| DeclineReason ||========================================
+
+type DeclineReason string
+
+const (
+	ReasonInvalidPrice      DeclineReason = "invalid_price"
+	ReasonInvalidRooms      DeclineReason = "invalid_rooms"
+	ReasonDuplicate         DeclineReason = "duplicate"
+	ReasonProhibitedContent DeclineReason = "prohibited_content"
+	ReasonIncompleteInfo    DeclineReason = "incomplete_info"
+	ReasonOther             DeclineReason = "other"
+)
+
+func ParseDeclineReason(str string) (DeclineReason, error) {
+	var dr DeclineReason
+
+	switch str {
+	case string(ReasonInvalidPrice):
+		dr = ReasonInvalidPrice
+	case string(ReasonInvalidRooms):
+		dr = ReasonInvalidRooms
+	case string(ReasonDuplicate):
+		dr = ReasonDuplicate
+	case string(ReasonProhibitedContent):
+		dr = ReasonProhibitedContent
+	case string(ReasonIncompleteInfo):
+		dr = ReasonIncompleteInfo
+	case string(ReasonOther):
+		dr = ReasonOther
+	default:
+		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
+	}
+
+	return dr, nil
+}
+
+func (dr *DeclineReason) Scan(value interface{}) error {
+	str, ok := value.([]byte)
+	if !ok {
+		return errors.New("faile type assertion")
+	}
+
+	reason, err := ParseDeclineReason(string(str))
+	if err != nil {
+		return err
+	}
+
+	*dr = reason
+	return nil
+}
+
+func (dr DeclineReason) Value() (driver.Value, error) {
+	return string(dr), nil
+}
+
+//======|| FlatLayout ||========================================
+
+type FlatLayout string
+
+const (
+	LayoutStudio   FlatLayout = "studio"
+	LayoutEuro2    FlatLayout = "euro_2"
+	LayoutEuro3    FlatLayout = "euro_3"
+	LayoutEuro4    FlatLayout = "euro_4"
+	LayoutClassic  FlatLayout = "classic"
+	LayoutOpenPlan FlatLayout = "open_plan"
+)
+
+func ParseFlatLayout(str string) (FlatLayout, error) {
+	var fl FlatLayout
+
+	switch str {
+	case string(LayoutStudio):
+		fl = LayoutStudio
+	case string(LayoutEuro2):
+		fl = LayoutEuro2
+	case string(LayoutEuro3):
+		fl = LayoutEuro3
+	case string(LayoutEuro4):
+		fl = LayoutEuro4
+	case string(LayoutClassic):
+		fl = LayoutClassic
+	case string(LayoutOpenPlan):
+		fl = LayoutOpenPlan
+	default:
+		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
+	}
+
+	return fl, nil
+}
+
+func (fl *FlatLayout) Scan(value interface{}) error {
+	str, ok := value.([]byte)
+	if !ok {
+		return errors.New("faile type assertion")
+	}
+
+	layout, err := ParseFlatLayout(string(str))
+	if err != nil {
+		return err
+	}
+
+	*fl = layout
+	return nil
+}
+
+func (fl FlatLayout) Value() (driver.Value, error) {
+	return string(fl), nil
+}
+
+//======|| RuleAction ||========================================
+
+type RuleAction string
+
+const (
+	RuleActionPass    RuleAction = "pass"
+	RuleActionFlag    RuleAction = "flag"
+	RuleActionDecline RuleAction = "decline"
+)
+
+func ParseRuleAction(str string) (RuleAction, error) {
+	var ra RuleAction
+
+	switch str {
+	case string(RuleActionPass):
+		ra = RuleActionPass
+	case string(RuleActionFlag):
+		ra = RuleActionFlag
+	case string(RuleActionDecline):
+		ra = RuleActionDecline
+	default:
+		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
+	}
+
+	return ra, nil
+}
+
+func (ra *RuleAction) Scan(value interface{}) error {
+	str, ok := value.([]byte)
+	if !ok {
+		return errors.New("faile type assertion")
+	}
+
+	action, err := ParseRuleAction(string(str))
+	if err != nil {
+		return err
+	}
+
+	*ra = action
+	return nil
+}
+
+func (ra RuleAction) Value() (driver.Value, error) {
+	return string(ra), nil
+}
+
+//======|| FlatSort ||========================================
+
+type FlatSort string
+
+const (
+	SortIDAsc     FlatSort = "id_asc"
+	SortIDDesc    FlatSort = "id_desc"
+	SortPriceAsc  FlatSort = "price_asc"
+	SortPriceDesc FlatSort = "price_desc"
+	SortRoomsAsc  FlatSort = "rooms_asc"
+	SortRoomsDesc FlatSort = "rooms_desc"
+)
+
+func ParseFlatSort(str string) (FlatSort, error) {
+	var fs FlatSort
+
+	switch str {
+	case string(SortIDAsc):
+		fs = SortIDAsc
+	case string(SortIDDesc):
+		fs = SortIDDesc
+	case string(SortPriceAsc):
+		fs = SortPriceAsc
+	case string(SortPriceDesc):
+		fs = SortPriceDesc
+	case string(SortRoomsAsc):
+		fs = SortRoomsAsc
+	case string(SortRoomsDesc):
+		fs = SortRoomsDesc
+	default:
+		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
+	}
+
+	return fs, nil
+}
+
+// Column returns the flats column the sort is applied to.
+func (fs FlatSort) Column() string {
+	switch fs {
+	case SortPriceAsc, SortPriceDesc:
+		return "price"
+	case SortRoomsAsc, SortRoomsDesc:
+		return "rooms"
+	default:
+		return "id"
+	}
+}
+
+// Desc reports whether the sort order is descending.
+func (fs FlatSort) Desc() bool {
+	return fs == SortIDDesc || fs == SortPriceDesc || fs == SortRoomsDesc
+}
+
+// CursorOf builds the cursor pointing right after the given flat.
+func (fs FlatSort) CursorOf(flat *Flat) Cursor {
+	var value int64
+
+	switch fs.Column() {
+	case "price":
+		value = flat.Price
+	case "rooms":
+		value = flat.Rooms
+	default:
+		value = flat.ID
+	}
+
+	return Cursor{Value: value, ID: flat.ID}
+}
+
+//======|| HouseSort ||========================================
+
+type HouseSort string
+
+const (
+	SortUpdatedAtDesc   HouseSort = "updated_at_desc"
+	SortUpdatedAtAsc    HouseSort = "updated_at_asc"
+	SortPublishedAtDesc HouseSort = "published_at_desc"
+)
+
+func ParseHouseSort(str string) (HouseSort, error) {
+	var hs HouseSort
+
+	switch str {
+	case string(SortUpdatedAtDesc):
+		hs = SortUpdatedAtDesc
+	case string(SortUpdatedAtAsc):
+		hs = SortUpdatedAtAsc
+	case string(SortPublishedAtDesc):
+		hs = SortPublishedAtDesc
+	default:
+		return "", errors.New(fmt.Sprintf("unknown enum value %s", str))
+	}
+
+	return hs, nil
+}
+
+// Column returns the houses column the sort is applied to.
+func (hs HouseSort) Column() string {
+	if hs == SortPublishedAtDesc {
+		return "last_flat_published_at"
+	}
+	return "updated_at"
+}
+
+// Desc reports whether the sort order is descending.
+func (hs HouseSort) Desc() bool {
+	return hs == SortUpdatedAtDesc || hs == SortPublishedAtDesc
+}
+
+// CursorOf builds the cursor pointing right after the given house.
+// Times are kept with microsecond precision, as stored in the database.
+func (hs HouseSort) CursorOf(house *House) Cursor {
+	sortedAt := house.UpdatedAt
+	if hs == SortPublishedAtDesc && house.LastFlatPublishedAt != nil {
+		sortedAt = *house.LastFlatPublishedAt
+	}
+	return Cursor{Value: sortedAt.UnixMicro(), ID: house.ID}
+}
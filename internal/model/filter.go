@@ -0,0 +1,50 @@
+package model
+
+import "github.com/google/uuid"
+
+// Параметры поиска квартир.
+// Нулевые значения полей означают отсутствие соответствующего фильтра.
+type FlatFilter struct {
+	HouseIDs  []int64
+	Rooms     []int64
+	PriceMin  int64
+	PriceMax  int64
+	Developer string
+	YearMin   int64
+	YearMax   int64
+	AreaMin   float64
+	AreaMax   float64
+	FloorMin  int64
+	FloorMax  int64
+	Layouts   []FlatLayout
+	Statuses  []FlatStatus
+	OwnerID   uuid.UUID
+	Sort      FlatSort
+	Limit     int64
+	After     *Cursor
+}
+
+// Страница списка квартир
+type FlatPage struct {
+	Flats      []*Flat
+	Total      int64
+	NextCursor string
+}
+
+// Параметры выборки домов.
+// Нулевые значения полей означают отсутствие соответствующего фильтра.
+type HouseFilter struct {
+	Developer string
+	YearMin   int64
+	YearMax   int64
+	Address   string
+	Sort      HouseSort
+	Limit     int64
+	After     *Cursor
+}
+
+// Страница списка домов
+type HousePage struct {
+	Houses     []*House
+	NextCursor string
+}
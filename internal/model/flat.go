@@ -1,40 +1,216 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Квартира
 type Flat struct {
-	ID      int64      `json:"id" db:"id"`
-	HouseID int64      `json:"house_id" db:"house_id"`
-	Price   int64      `json:"price" db:"price"`
-	Rooms   int64      `json:"rooms" db:"rooms"`
-	Status  FlatStatus `json:"status" db:"status"`
+	ID            int64    `json:"id" db:"id"`
+	HouseID       int64    `json:"house_id" db:"house_id"`
+	Number        int64    `json:"number" db:"number"`
+	Price         int64    `json:"price" db:"price"`
+	PreviousPrice *int64   `json:"previous_price,omitempty" db:"previous_price"`
+	PriceChange   *float64 `json:"price_change_percent,omitempty" db:"-"`
+	Rooms         int64    `json:"rooms" db:"rooms"`
+	FlatAttributes
+	Status              FlatStatus     `json:"status" db:"status"`
+	Flagged             bool           `json:"flagged,omitempty" db:"flagged"`
+	OwnerID             *uuid.UUID     `json:"owner_id,omitempty" db:"owner_id"`
+	ModeratorID         *uuid.UUID     `json:"-" db:"moderator_id"`
+	ModerationStartedAt *time.Time     `json:"moderation_started_at,omitempty" db:"moderation_started_at"`
+	DeclineReason       *DeclineReason `json:"decline_reason,omitempty" db:"decline_reason"`
+	DeclineComment      *string        `json:"decline_comment,omitempty" db:"decline_comment"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	Version             int64          `json:"version" db:"version"`
+	Photos              []*FlatPhoto   `json:"photos,omitempty" db:"-"`
+}
+
+// Дополнительные характеристики квартиры, необязательные для заполнения.
+// Площади указываются в квадратных метрах.
+type FlatAttributes struct {
+	TotalArea   *float64    `json:"total_area,omitempty" db:"total_area"`
+	KitchenArea *float64    `json:"kitchen_area,omitempty" db:"kitchen_area"`
+	Floor       *int64      `json:"floor,omitempty" db:"floor"`
+	Description *string     `json:"description,omitempty" db:"description"`
+	Layout      *FlatLayout `json:"layout,omitempty" db:"layout"`
+}
+
+// Данные для создания квартиры
+type NewFlat struct {
+	HouseID int64
+	Number  int64
+	Price   int64
+	Rooms   int64
+	OwnerID uuid.UUID
+	FlatAttributes
+}
+
+// Изменение параметров квартиры владельцем.
+// Пустые поля остаются без изменений.
+type FlatEdit struct {
+	Price *int64
+	Rooms *int64
+	FlatAttributes
+}
+
+// Запрошенное изменение статуса квартиры.
+// Причина и комментарий указываются только при отклонении.
+// Ненулевая версия должна совпадать с текущей версией квартиры.
+type FlatStatusUpdate struct {
+	Status  FlatStatus
+	Reason  DeclineReason
+	Comment string
+	Version int64
+}
+
+// Результат модерации одной квартиры из пакета.
+// Заполняется либо обновленная квартира, либо ошибка перехода.
+type FlatModerationResult struct {
+	FlatID int64
+	Flat   *Flat
+	Err    error
 }
 
 var (
 	ErrImpossibleTransition = errors.New("impossible status transition")
+	ErrForeignModeration    = errors.New("flat is on moderation by another moderator")
+	ErrDeclineReasonMissing = errors.New("decline reason is required")
+	ErrFlatOnModeration     = errors.New("flat can not be edited while on moderation")
+	ErrFlatClosed           = errors.New("flat is archived or sold")
+	ErrKitchenAreaTooLarge  = errors.New("kitchen area must not exceed total area")
 )
 
-func (f *Flat) Approve() error {
-	if f.Status != StatusOnModeration {
-		return ErrImpossibleTransition
+// Edit changes the flat parameters on behalf of the actor. Approved and declined
+// flats are returned to created status to pass the moderation again, the returned
+// transition is empty if the status is kept.
+func (f *Flat) Edit(edit FlatEdit, actor Actor) (FlatTransition, error) {
+	if err := f.checkEditable(); err != nil {
+		return FlatTransition{}, err
 	}
-	f.Status = StatusApproved
-	return nil
+	attributes := f.FlatAttributes.merge(edit.FlatAttributes)
+	if err := attributes.Validate(); err != nil {
+		return FlatTransition{}, err
+	}
+	transition, err := f.resubmit(actor)
+	if err != nil {
+		return FlatTransition{}, err
+	}
+	if edit.Price != nil && *edit.Price != f.Price {
+		previous := f.Price
+		f.PreviousPrice = &previous
+		f.Price = *edit.Price
+	}
+	if edit.Rooms != nil {
+		f.Rooms = *edit.Rooms
+	}
+	f.FlatAttributes = attributes
+	return transition, nil
 }
 
-func (f *Flat) Decline() error {
-	if f.Status != StatusOnModeration {
-		return ErrImpossibleTransition
+// AddPhoto checks that the actor can add a photo to the flat. Approved and
+// declined flats are returned to created status to pass the moderation again,
+// the returned transition is empty if the status is kept.
+func (f *Flat) AddPhoto(actor Actor) (FlatTransition, error) {
+	if err := f.checkEditable(); err != nil {
+		return FlatTransition{}, err
+	}
+	return f.resubmit(actor)
+}
+
+// RemovePhoto checks that a photo can be removed from the flat.
+func (f *Flat) RemovePhoto() error {
+	return f.checkEditable()
+}
+
+// checkEditable reports whether the owner is allowed to change the flat.
+func (f *Flat) checkEditable() error {
+	if f.Status == StatusOnModeration {
+		return ErrFlatOnModeration
+	}
+	if f.Closed() {
+		return ErrFlatClosed
 	}
-	f.Status = StatusDeclined
 	return nil
 }
 
-func (f *Flat) StartModeration() error {
-	if f.Status != StatusCreated {
-		return ErrImpossibleTransition
+// resubmit returns a moderated flat to created status if the transition
+// table allows it for the actor. Flats in other statuses are left as is.
+func (f *Flat) resubmit(actor Actor) (FlatTransition, error) {
+	if f.Status != StatusApproved && f.Status != StatusDeclined {
+		return FlatTransition{}, nil
+	}
+	return f.ChangeStatus(FlatStatusUpdate{Status: StatusCreated}, actor)
+}
+
+// FillPriceChange computes the percentage change of the price
+// relative to the previous one, rounded to hundredths.
+func (f *Flat) FillPriceChange() {
+	f.PriceChange = nil
+	if f.PreviousPrice == nil || *f.PreviousPrice == 0 {
+		return
+	}
+	change := float64(f.Price-*f.PreviousPrice) / float64(*f.PreviousPrice) * 100
+	change = math.Round(change*100) / 100
+	f.PriceChange = &change
+}
+
+// Closed reports whether the flat has left the catalogue for good.
+func (f *Flat) Closed() bool {
+	return f.Status == StatusArchived || f.Status == StatusSold
+}
+
+// OwnedBy reports whether the flat was created by the given user.
+func (f *Flat) OwnedBy(userID uuid.UUID) bool {
+	return f.OwnerID != nil && *f.OwnerID == userID
+}
+
+// HideFrom clears the fields the actor is not allowed to see. The owner is shown
+// only to the owner and moderators, the premoderation flag only to moderators.
+func (f *Flat) HideFrom(actor Actor) {
+	if actor.Role == Moderator {
+		return
+	}
+	if !f.OwnedBy(actor.ID) {
+		f.OwnerID = nil
+	}
+	f.Flagged = false
+}
+
+// ModeratedBy reports whether the moderation of the flat belongs to the given moderator.
+// Flats taken on moderation before owners were recorded belong to every moderator.
+func (f *Flat) ModeratedBy(moderatorID uuid.UUID) bool {
+	return f.ModeratorID == nil || *f.ModeratorID == moderatorID
+}
+
+// Validate checks the attributes for consistency with each other.
+func (a FlatAttributes) Validate() error {
+	if a.TotalArea != nil && a.KitchenArea != nil && *a.KitchenArea > *a.TotalArea {
+		return ErrKitchenAreaTooLarge
 	}
-	f.Status = StatusOnModeration
 	return nil
 }
+
+// merge returns the attributes overridden by the set fields of the other ones.
+func (a FlatAttributes) merge(other FlatAttributes) FlatAttributes {
+	if other.TotalArea != nil {
+		a.TotalArea = other.TotalArea
+	}
+	if other.KitchenArea != nil {
+		a.KitchenArea = other.KitchenArea
+	}
+	if other.Floor != nil {
+		a.Floor = other.Floor
+	}
+	if other.Description != nil {
+		a.Description = other.Description
+	}
+	if other.Layout != nil {
+		a.Layout = other.Layout
+	}
+	return a
+}
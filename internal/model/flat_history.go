@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Запись истории изменения статуса квартиры
+type FlatStatusChange struct {
+	ID        int64      `json:"id" db:"id"`
+	FlatID    int64      `json:"flat_id" db:"flat_id"`
+	OldStatus FlatStatus `json:"old_status" db:"old_status"`
+	NewStatus FlatStatus `json:"new_status" db:"new_status"`
+	ActorID   uuid.UUID  `json:"actor_id" db:"actor_id"`
+	ActorRole UserType   `json:"actor_role" db:"actor_role"`
+	Reason    *string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Запись истории изменения цены квартиры
+type FlatPriceChange struct {
+	ID        int64     `json:"id" db:"id"`
+	FlatID    int64     `json:"flat_id" db:"flat_id"`
+	Price     int64     `json:"price" db:"price"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
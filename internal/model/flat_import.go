@@ -0,0 +1,8 @@
+package model
+
+// Результат импорта одной строки.
+// Заполняется либо созданная квартира, либо ошибка.
+type FlatImportResult struct {
+	Flat *Flat
+	Err  error
+}
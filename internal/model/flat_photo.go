@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Фотография квартиры. Файлы хранятся в blob-хранилище, в базе только их ключи
+type FlatPhoto struct {
+	ID           int64     `json:"id" db:"id"`
+	FlatID       int64     `json:"flat_id" db:"flat_id"`
+	Key          string    `json:"-" db:"key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	Size         int64     `json:"size" db:"size"`
+	URL          string    `json:"url" db:"-"`
+	ThumbnailURL string    `json:"thumbnail_url" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"math"
+)
+
+// Координаты точки на поверхности Земли в градусах
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude" yaml:"latitude"`
+	Longitude float64 `json:"longitude" yaml:"longitude"`
+}
+
+// Результат геокодирования адреса.
+// Координаты отсутствуют, если геокодер их не определяет.
+type GeoAddress struct {
+	Address string
+	Point   *GeoPoint
+}
+
+var ErrAddressNotFound = errors.New("address is not recognized by geocoder")
+
+const earthRadius = 6371000.0
+
+// DistanceTo returns the great-circle distance to the other point in meters.
+func (p GeoPoint) DistanceTo(other GeoPoint) float64 {
+	lat1, lat2 := radians(p.Latitude), radians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(other.Longitude - p.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Around returns the bounds of the square around the point
+// that contains every point within the given distance in meters.
+func (p GeoPoint) Around(distance float64) (lower, upper GeoPoint) {
+	latDelta := distance / earthRadius * 180 / math.Pi
+	lonDelta := latDelta / math.Max(math.Cos(radians(p.Latitude)), 1e-6)
+
+	lower = GeoPoint{Latitude: p.Latitude - latDelta, Longitude: p.Longitude - lonDelta}
+	upper = GeoPoint{Latitude: p.Latitude + latDelta, Longitude: p.Longitude + lonDelta}
+	return lower, upper
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -7,10 +7,74 @@ import (
 
 // Дом
 type House struct {
-	ID                 int64          `json:"id" db:"id"`
-	Address            string         `json:"address" db:"address"`
-	YearOfConstruction int64          `json:"year_of_construction" db:"year_of_construction"`
-	Developer          sql.NullString `json:"developer,omitempty" db:"developer"`
-	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	ID                  int64          `json:"id" db:"id"`
+	Address             string         `json:"address" db:"address"`
+	AddressKey          string         `json:"-" db:"address_key"`
+	YearOfConstruction  int64          `json:"year_of_construction" db:"year_of_construction"`
+	Developer           sql.NullString `json:"developer,omitempty" db:"developer"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at" db:"updated_at"`
+	LastFlatPublishedAt *time.Time     `json:"last_flat_published_at,omitempty" db:"last_flat_published_at"`
+	Latitude            *float64       `json:"latitude,omitempty" db:"latitude"`
+	Longitude           *float64       `json:"longitude,omitempty" db:"longitude"`
+}
+
+// Данные для создания дома
+type NewHouse struct {
+	Address    string
+	AddressKey string
+	Developer  string
+	Year       int64
+	Point      *GeoPoint
+}
+
+// Дом с количеством опубликованных квартир и подписчиков
+type HouseSummary struct {
+	House
+	ApprovedFlats int64 `json:"approved_flats" db:"approved_flats"`
+	Subscribers   int64 `json:"subscribers" db:"subscribers"`
+}
+
+// Изменение параметров дома модератором.
+// Пустые поля остаются без изменений, пустой застройщик сбрасывается.
+type HouseEdit struct {
+	Address   *string
+	Developer *string
+	Year      *int64
+}
+
+// Empty reports whether the edit changes nothing.
+func (e HouseEdit) Empty() bool {
+	return e.Address == nil && e.Developer == nil && e.Year == nil
+}
+
+// Point returns the coordinates of the house, if they are known.
+func (h *House) Point() *GeoPoint {
+	if h.Latitude == nil || h.Longitude == nil {
+		return nil
+	}
+	return &GeoPoint{Latitude: *h.Latitude, Longitude: *h.Longitude}
+}
+
+// Locate sets the geocoded address and coordinates of the house.
+func (h *House) Locate(address *GeoAddress) {
+	h.Address = address.Address
+	h.Latitude, h.Longitude = nil, nil
+	if address.Point != nil {
+		h.Latitude = &address.Point.Latitude
+		h.Longitude = &address.Point.Longitude
+	}
+}
+
+// Edit changes the house parameters.
+func (h *House) Edit(edit HouseEdit) {
+	if edit.Address != nil {
+		h.Address = *edit.Address
+	}
+	if edit.Developer != nil {
+		h.Developer = sql.NullString{String: *edit.Developer, Valid: *edit.Developer != ""}
+	}
+	if edit.Year != nil {
+		h.YearOfConstruction = *edit.Year
+	}
 }
@@ -0,0 +1,17 @@
+package model
+
+// Статистика цен одобренных квартир дома с одинаковым числом комнат
+type RoomsPriceStats struct {
+	Rooms       int64   `json:"rooms" db:"rooms"`
+	Count       int64   `json:"count" db:"count"`
+	MinPrice    int64   `json:"min_price" db:"min_price"`
+	MaxPrice    int64   `json:"max_price" db:"max_price"`
+	AvgPrice    float64 `json:"avg_price" db:"avg_price"`
+	MedianPrice float64 `json:"median_price" db:"median_price"`
+}
+
+// Статистика цен одобренных квартир дома по числу комнат
+type HousePriceStats struct {
+	HouseID int64              `json:"house_id"`
+	Rooms   []*RoomsPriceStats `json:"rooms"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Решение одного правила премодерации по квартире.
+// Если ни одно правило не сработало, записывается решение pass без правила.
+type RuleDecision struct {
+	ID        int64          `json:"id" db:"id"`
+	FlatID    int64          `json:"flat_id" db:"flat_id"`
+	Rule      *string        `json:"rule,omitempty" db:"rule"`
+	Action    RuleAction     `json:"action" db:"action"`
+	Reason    *DeclineReason `json:"reason,omitempty" db:"reason"`
+	Details   *string        `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Параметры постраничной выборки решений премодерации по квартире.
+// After - курсор последнего решения предыдущей страницы
+type RuleDecisionFilter struct {
+	Limit int64
+	After *Cursor
+}
+
+// Страница решений премодерации по квартире
+type RuleDecisionPage struct {
+	Decisions  []*RuleDecision
+	NextCursor string
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"slices"
+)
+
+// Условие перехода, проверяемое помимо роли пользователя.
+// Переходы от имени системы условиями не ограничиваются
+type TransitionGuard int
+
+const (
+	GuardNone TransitionGuard = iota
+	// Пользователь должен быть владельцем квартиры
+	GuardOwner
+	// Пользователь должен вести модерацию квартиры
+	GuardModerator
+)
+
+// Переход квартиры из одного статуса в другой.
+// Event публикуется для подписчиков дома, при пакетной модерации с BatchEvent
+// публикуется одно событие на дом для всех квартир пакета.
+// InvalidateCache сбрасывает закешированные списки квартир дома,
+// MarkPublished обновляет время последней публикации квартиры в доме
+type FlatTransition struct {
+	From            FlatStatus      `json:"from"`
+	To              FlatStatus      `json:"to"`
+	Roles           []UserType      `json:"-"`
+	Guard           TransitionGuard `json:"-"`
+	Event           EventType       `json:"-"`
+	BatchEvent      bool            `json:"-"`
+	InvalidateCache bool            `json:"-"`
+	MarkPublished   bool            `json:"-"`
+}
+
+// Таблица допустимых переходов статусов квартиры
+type TransitionTable []FlatTransition
+
+// FlatTransitions describes the whole lifecycle of a flat. Transitions from
+// or to approved status change the set of flats visible to clients.
+var FlatTransitions = TransitionTable{
+	// moderation
+	{From: StatusCreated, To: StatusOnModeration, Roles: []UserType{Moderator}},
+	{From: StatusOnModeration, To: StatusApproved, Roles: []UserType{Moderator}, Guard: GuardModerator, Event: FlatApproved, BatchEvent: true, InvalidateCache: true, MarkPublished: true},
+	{From: StatusOnModeration, To: StatusDeclined, Roles: []UserType{Moderator}, Guard: GuardModerator},
+	{From: StatusOnModeration, To: StatusCreated, Roles: []UserType{Moderator, System}, Guard: GuardModerator},
+	{From: StatusApproved, To: StatusOnModeration, Roles: []UserType{Moderator}, InvalidateCache: true},
+	{From: StatusCreated, To: StatusDeclined, Roles: []UserType{System}},
+
+	// resubmission
+	{From: StatusDeclined, To: StatusCreated, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusApproved, To: StatusCreated, Roles: []UserType{Client, Moderator}, Guard: GuardOwner, InvalidateCache: true},
+
+	// closing
+	{From: StatusCreated, To: StatusArchived, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusDeclined, To: StatusArchived, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusApproved, To: StatusArchived, Roles: []UserType{Client, Moderator}, Guard: GuardOwner, Event: FlatArchived, InvalidateCache: true},
+	{From: StatusCreated, To: StatusSold, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusDeclined, To: StatusSold, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusArchived, To: StatusSold, Roles: []UserType{Client, Moderator}, Guard: GuardOwner},
+	{From: StatusApproved, To: StatusSold, Roles: []UserType{Client, Moderator}, Guard: GuardOwner, Event: FlatSold, InvalidateCache: true},
+}
+
+var ErrTransitionForbidden = errors.New("status transition is not allowed for this user")
+
+// Find returns the transition of the flat to the status allowed for the actor.
+func (t TransitionTable) Find(flat *Flat, to FlatStatus, actor Actor) (FlatTransition, error) {
+	for _, transition := range t {
+		if transition.From == flat.Status && transition.To == to {
+			return transition, transition.check(flat, actor)
+		}
+	}
+	// the owner can not touch the flat until the moderator decides on it
+	if flat.Status == StatusOnModeration && flat.OwnedBy(actor.ID) {
+		return FlatTransition{}, ErrFlatOnModeration
+	}
+	return FlatTransition{}, ErrImpossibleTransition
+}
+
+// Available lists the transitions of the flat allowed for the actor.
+func (t TransitionTable) Available(flat *Flat, actor Actor) []FlatTransition {
+	transitions := []FlatTransition{}
+	for _, transition := range t {
+		if transition.From == flat.Status && transition.check(flat, actor) == nil {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions
+}
+
+// check reports whether the actor is allowed to move the flat along the transition.
+func (t FlatTransition) check(flat *Flat, actor Actor) error {
+	if !slices.Contains(t.Roles, actor.Role) {
+		return ErrTransitionForbidden
+	}
+	if actor.Role == System {
+		return nil
+	}
+	switch t.Guard {
+	case GuardOwner:
+		if !flat.OwnedBy(actor.ID) {
+			return ErrTransitionForbidden
+		}
+	case GuardModerator:
+		if !flat.ModeratedBy(actor.ID) {
+			return ErrForeignModeration
+		}
+	}
+	return nil
+}
+
+// ChangeStatus moves the flat to the requested status if the transition table
+// allows it for the actor, and returns the transition to apply its side effects.
+// A reason is required to decline the flat.
+func (f *Flat) ChangeStatus(update FlatStatusUpdate, actor Actor) (FlatTransition, error) {
+	transition, err := FlatTransitions.Find(f, update.Status, actor)
+	if err != nil {
+		return FlatTransition{}, err
+	}
+
+	switch update.Status {
+	case StatusOnModeration:
+		f.ModeratorID = &actor.ID
+	case StatusApproved:
+		f.DeclineReason = nil
+		f.DeclineComment = nil
+	case StatusDeclined:
+		if update.Reason == "" {
+			return FlatTransition{}, ErrDeclineReasonMissing
+		}
+		reason := update.Reason
+		f.DeclineReason = &reason
+		f.DeclineComment = nil
+		if update.Comment != "" {
+			comment := update.Comment
+			f.DeclineComment = &comment
+		}
+	case StatusCreated:
+		f.DeclineReason = nil
+		f.DeclineComment = nil
+	}
+	// the moderator is kept only while the moderation lasts
+	if update.Status != StatusOnModeration {
+		f.ModeratorID = nil
+	}
+	f.Status = update.Status
+
+	return transition, nil
+}
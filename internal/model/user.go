@@ -8,3 +8,12 @@ type User struct {
 	Password string    `db:"password"`
 	Type     UserType  `db:"type"`
 }
+
+// Пользователь, от имени которого выполняется действие
+type Actor struct {
+	ID   uuid.UUID
+	Role UserType
+}
+
+// Автоматические действия выполняются от имени системы
+var SystemActor = Actor{ID: uuid.Nil, Role: System}
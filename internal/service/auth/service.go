@@ -14,7 +14,7 @@ import (
 )
 
 type JWT interface {
-	CreateToken(role string) (string, error)
+	CreateToken(userID, role string) (string, error)
 }
 
 type UserRepository interface {
@@ -39,7 +39,8 @@ func New(log *slog.Logger, jwt JWT, repository UserRepository) *Service {
 func (s *Service) DummyLogin(ctx context.Context, role model.UserType) (string, error) {
 	const op = "Auth.DummyLogin"
 
-	token, err := s.jwt.CreateToken(string(role))
+	// dummy users are not persisted, so every token gets its own identity
+	token, err := s.jwt.CreateToken(uuid.New().String(), string(role))
 	if err != nil {
 		return "", err
 	}
@@ -80,7 +81,7 @@ func (s *Service) Login(ctx context.Context, ID uuid.UUID, password string) (str
 
 	log.Info("user logged in successfully")
 
-	token, err := s.jwt.CreateToken(string(user.Type))
+	token, err := s.jwt.CreateToken(user.ID.String(), string(user.Type))
 	if err != nil {
 		log.Error("failed to generate token", sl.Err(err))
 		return "", fmt.Errorf("%s: %w", op, err)
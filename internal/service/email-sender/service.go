@@ -63,7 +63,9 @@ func New(
 }
 
 type Payload struct {
-	HouseID int64 `json:"house_id"`
+	HouseID int64   `json:"house_id"`
+	FlatID  int64   `json:"flat_id,omitempty"`
+	FlatIDs []int64 `json:"flat_ids,omitempty"`
 }
 
 // StartProcessEvents starts a goroutine that processes events periodically.
@@ -117,25 +119,52 @@ func (s *Service) processEvent(ctx context.Context) error {
 
 	house, err := s.houseRepository.GetHouse(ctx, payload.HouseID)
 	if err != nil {
+		// The house has been deleted, nobody is left to notify
+		if errors.Is(err, repository.ErrNotFound) {
+			s.log.Info("skip event of deleted house", slog.Int64("event_id", event.ID), slog.Int64("house_id", payload.HouseID))
+			return s.setDone(ctx, event.ID)
+		}
 		return fmt.Errorf("failed to get house by ID: %w", err)
 	}
 
+	message, err := composeEmailMessage(event.Type, payload, house)
+	if err != nil {
+		return fmt.Errorf("failed to compose email message: %w", err)
+	}
+
 	// Send emails to subscribers
 	for _, sub := range subscribers {
 		s.retrier.Retry(ctx, func() error {
-			return s.sender.SendEmail(ctx, sub.Email, composeEmailMessage(house))
+			return s.sender.SendEmail(ctx, sub.Email, message)
 		})
 	}
 
 	// Mark the event as done
-	if err := s.eventRepository.SetDone(ctx, event.ID); err != nil {
+	return s.setDone(ctx, event.ID)
+}
+
+// setDone marks the event as processed.
+func (s *Service) setDone(ctx context.Context, eventID int64) error {
+	if err := s.eventRepository.SetDone(ctx, eventID); err != nil {
 		return fmt.Errorf("failed to set event done: %w", err)
 	}
 
 	return nil
 }
 
-// composeEmailMessage composes the email message.
-func composeEmailMessage(house *model.House) string {
-	return fmt.Sprintf("В доме по адресу %s появилось новое объявление", house.Address)
+// composeEmailMessage composes the email message for the event type.
+func composeEmailMessage(eventType model.EventType, payload Payload, house *model.House) (string, error) {
+	switch eventType {
+	case model.FlatApproved:
+		if len(payload.FlatIDs) > 1 {
+			return fmt.Sprintf("В доме по адресу %s появились новые объявления: %d", house.Address, len(payload.FlatIDs)), nil
+		}
+		return fmt.Sprintf("В доме по адресу %s появилось новое объявление", house.Address), nil
+	case model.FlatArchived:
+		return fmt.Sprintf("В доме по адресу %s объявление снято с публикации", house.Address), nil
+	case model.FlatSold:
+		return fmt.Sprintf("В доме по адресу %s квартира продана", house.Address), nil
+	default:
+		return "", fmt.Errorf("unknown event type %s", eventType)
+	}
 }
@@ -0,0 +1,139 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+)
+
+// batchEventPayload is the payload of the event published once per house
+// for all flats of the batch that made the same transition.
+type batchEventPayload struct {
+	HouseID int64   `json:"house_id"`
+	FlatIDs []int64 `json:"flat_ids"`
+}
+
+// batchEventKey groups the flats of the batch by the event and the house.
+type batchEventKey struct {
+	event   model.EventType
+	houseID int64
+}
+
+// ModerateFlats applies the same status update to every flat in one transaction.
+// Transition errors of individual flats are reported in the results, which are
+// returned in the order of the IDs. Events of transitions declared as batch
+// events are published once per house for all flats of the batch.
+func (s *Service) ModerateFlats(ctx context.Context, IDs []int64, update model.FlatStatusUpdate, actor model.Actor) (results []model.FlatModerationResult, err error) {
+	const op = "flat.ModerateFlats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("flats", len(IDs)),
+		slog.String("status", string(update.Status)),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		results = make([]model.FlatModerationResult, len(IDs))
+		events := make(map[batchEventKey][]int64)
+
+		for i, ID := range IDs {
+			results[i].FlatID = ID
+
+			flat, err := s.flatRepository.GetFlat(ctx, ID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					results[i].Err = ErrFlatNotExist
+					continue
+				}
+				log.Error("failed to find flat", slog.Int64("flat_id", ID), sl.Err(err))
+				return err
+			}
+
+			oldStatus := flat.Status
+			transition, err := flat.ChangeStatus(update, actor)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+
+			flat, err = s.updateFlat(ctx, flat)
+			if errors.Is(err, ErrFlatModified) {
+				results[i].Err = err
+				continue
+			}
+			if err != nil {
+				log.Error("failed to update flat in db", slog.Int64("flat_id", ID), sl.Err(err))
+				return err
+			}
+
+			err = s.saveStatusChange(ctx, flat, oldStatus, actor)
+			if err != nil {
+				log.Error("failed to save status change", slog.Int64("flat_id", ID), sl.Err(err))
+				return err
+			}
+
+			// the batch event is published below once per house
+			if transition.BatchEvent && transition.Event != "" {
+				key := batchEventKey{event: transition.Event, houseID: flat.HouseID}
+				events[key] = append(events[key], flat.ID)
+				transition.Event = ""
+			}
+
+			err = s.applyTransitionEffects(ctx, flat, transition)
+			if err != nil {
+				log.Error("failed to apply transition effects", slog.Int64("flat_id", ID), sl.Err(err))
+				return err
+			}
+
+			results[i].Flat = flat
+		}
+
+		return s.publishBatchEvents(ctx, events)
+	})
+
+	if err != nil {
+		log.Error("failed to moderate flats", sl.Err(err))
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// publishBatchEvents publishes one event for every house with the flats
+// that made the transition in the batch, houses in ascending order.
+func (s *Service) publishBatchEvents(ctx context.Context, events map[batchEventKey][]int64) error {
+	keys := make([]batchEventKey, 0, len(events))
+	for key := range events {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].houseID != keys[j].houseID {
+			return keys[i].houseID < keys[j].houseID
+		}
+		return keys[i].event < keys[j].event
+	})
+
+	for _, key := range keys {
+		payload, err := json.Marshal(batchEventPayload{
+			HouseID: key.houseID,
+			FlatIDs: events[key],
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.eventRepository.PublishEvent(ctx, key.event, string(payload))
+		if err != nil {
+			s.log.Error("failed to publish event", slog.Int64("house_id", key.houseID), sl.Err(err))
+			return err
+		}
+	}
+
+	return nil
+}
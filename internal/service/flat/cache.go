@@ -0,0 +1,45 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"fmt"
+	"strings"
+)
+
+// houseCachePrefix returns the prefix shared by all cache keys of the house.
+func houseCachePrefix(houseID int64) string {
+	return fmt.Sprintf("house:%d:", houseID)
+}
+
+// flatPageCacheKey builds the cache key for a page of flats of the house.
+func flatPageCacheKey(houseID int64, filter model.FlatFilter) string {
+	var after string
+	if filter.After != nil {
+		after = filter.After.Encode()
+	}
+
+	key := fmt.Sprintf("%s%s:%d:%s", houseCachePrefix(houseID), filter.Sort, filter.Limit, after)
+	if filter.AreaMin > 0 || filter.AreaMax > 0 {
+		key += fmt.Sprintf(":area=%g-%g", filter.AreaMin, filter.AreaMax)
+	}
+	if filter.FloorMin > 0 || filter.FloorMax > 0 {
+		key += fmt.Sprintf(":floor=%d-%d", filter.FloorMin, filter.FloorMax)
+	}
+	if len(filter.Layouts) > 0 {
+		layouts := make([]string, 0, len(filter.Layouts))
+		for _, layout := range filter.Layouts {
+			layouts = append(layouts, string(layout))
+		}
+		key += ":layout=" + strings.Join(layouts, ",")
+	}
+
+	return key
+}
+
+// invalidateHouseCache removes every cached page of flats of the house.
+func (s *Service) invalidateHouseCache(houseID int64) {
+	prefix := houseCachePrefix(houseID)
+	s.cache.RemoveFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
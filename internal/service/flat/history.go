@@ -0,0 +1,145 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+var ErrHistoryForbidden = errors.New("only moderators can see the moderation history of the flat")
+
+// GetFlatHistory retrieves the status history of the flat in chronological order.
+// The history is available to moderators only.
+func (s *Service) GetFlatHistory(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatStatusChange, error) {
+	const op = "flat.GetFlatHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	if actor.Role != model.Moderator {
+		log.Error("attempt to see flat history by non-moderator")
+		return nil, ErrHistoryForbidden
+	}
+
+	_, err := s.flatRepository.GetFlat(ctx, flatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("flat does not exist", sl.Err(err))
+			return nil, ErrFlatNotExist
+		}
+		log.Error("failed to find flat", sl.Err(err))
+		return nil, err
+	}
+
+	history, err := s.historyRepository.FlatStatusHistory(ctx, flatID)
+	if err != nil {
+		log.Error("failed to get flat history", sl.Err(err))
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return []*model.FlatStatusChange{}, nil
+	}
+
+	return history, nil
+}
+
+// saveStatusChange records the transition of the flat from the old status
+// to its current one. It is expected to run in the transaction that updates the flat.
+func (s *Service) saveStatusChange(ctx context.Context, flat *model.Flat, oldStatus model.FlatStatus, actor model.Actor) error {
+	return s.historyRepository.SaveFlatStatusChange(ctx, &model.FlatStatusChange{
+		FlatID:    flat.ID,
+		OldStatus: oldStatus,
+		NewStatus: flat.Status,
+		ActorID:   actor.ID,
+		ActorRole: actor.Role,
+	})
+}
+
+// GetFlatPriceHistory retrieves the price history of the flat in chronological order.
+// The history is available to those who can see the flat itself.
+func (s *Service) GetFlatPriceHistory(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatPriceChange, error) {
+	const op = "flat.GetFlatPriceHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+	)
+
+	_, err := s.GetFlat(ctx, flatID, actor)
+	if err != nil {
+		log.Error("failed to get flat", sl.Err(err))
+		return nil, err
+	}
+
+	history, err := s.historyRepository.FlatPriceHistory(ctx, flatID)
+	if err != nil {
+		log.Error("failed to get flat price history", sl.Err(err))
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return []*model.FlatPriceChange{}, nil
+	}
+
+	return history, nil
+}
+
+// GetFlatRuleDecisions retrieves a page of the decisions of the premoderation
+// rules made on the flat in chronological order. The decisions are available
+// to moderators only.
+func (s *Service) GetFlatRuleDecisions(ctx context.Context, flatID int64, actor model.Actor, filter model.RuleDecisionFilter) (*model.RuleDecisionPage, error) {
+	const op = "flat.GetFlatRuleDecisions"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	if actor.Role != model.Moderator {
+		log.Error("attempt to see flat decisions by non-moderator")
+		return nil, ErrHistoryForbidden
+	}
+
+	_, err := s.flatRepository.GetFlat(ctx, flatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("flat does not exist", sl.Err(err))
+			return nil, ErrFlatNotExist
+		}
+		log.Error("failed to find flat", sl.Err(err))
+		return nil, err
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		filter.Limit = defaultPageSize
+	}
+	limit := filter.Limit
+
+	// Fetch one extra decision to find out whether the next page exists
+	filter.Limit++
+	decisions, err := s.historyRepository.FlatRuleDecisions(ctx, flatID, filter)
+	if err != nil {
+		log.Error("failed to get flat rule decisions", sl.Err(err))
+		return nil, err
+	}
+
+	page := &model.RuleDecisionPage{Decisions: decisions}
+	if int64(len(decisions)) > limit {
+		page.Decisions = decisions[:limit]
+		last := page.Decisions[limit-1]
+		page.NextCursor = model.Cursor{Value: last.ID, ID: last.ID}.Encode()
+	}
+	if page.Decisions == nil {
+		page.Decisions = []*model.RuleDecision{}
+	}
+
+	return page, nil
+}
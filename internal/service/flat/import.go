@@ -0,0 +1,64 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+	"github.com/google/uuid"
+)
+
+// every imported row is saved under its own savepoint,
+// so a failed row does not abort the whole import transaction
+var nestedTx = settings.Must(settings.WithPropagation(trm.PropagationNested))
+
+// ImportFlats saves the rows in a single transaction on behalf of the owner.
+// Rows with inconsistent attributes, referencing unknown houses or taken flat numbers
+// are skipped and reported in the results, which are returned in the order of the rows.
+func (s *Service) ImportFlats(ctx context.Context, rows []model.NewFlat, ownerID uuid.UUID) ([]model.FlatImportResult, error) {
+	const op = "flat.ImportFlats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+		slog.String("owner_id", ownerID.String()),
+	)
+
+	results := make([]model.FlatImportResult, len(rows))
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		for i, row := range rows {
+			err := s.trManager.DoWithSettings(ctx, nestedTx, func(ctx context.Context) error {
+				row.OwnerID = ownerID
+				flat, err := s.saveFlat(ctx, row)
+				if err != nil {
+					return err
+				}
+				results[i].Flat = flat
+				return nil
+			})
+			if err != nil {
+				if errors.Is(err, model.ErrKitchenAreaTooLarge) ||
+					errors.Is(err, repository.ErrConstraintViolation) ||
+					errors.Is(err, repository.ErrAlreadyExists) {
+					results[i].Err = saveFlatError(err)
+					continue
+				}
+				log.Error("failed to save flat", slog.Int("row", i), sl.Err(err))
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Error("failed to import flats", sl.Err(err))
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -3,13 +3,53 @@ package flat
 import (
 	"avito-backend-bootcamp/internal/model"
 	"context"
+	"io"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 )
 
 type FlatRepository interface {
 	GetFlat(ctx context.Context, ID int64) (*model.Flat, error)
-	SaveFlat(ctx context.Context, houseID, price, fooms int64) (*model.Flat, error)
+	SaveFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error)
 	UpdateFlat(ctx context.Context, flat *model.Flat) (*model.Flat, error)
-	FlatListByHouseID(ctx context.Context, houseID int64) ([]*model.Flat, error)
+	SearchFlats(ctx context.Context, filter model.FlatFilter) ([]*model.Flat, error)
+	CountFlats(ctx context.Context, filter model.FlatFilter) (int64, error)
+	FlatModerationQueue(ctx context.Context, limit int64) ([]*model.Flat, error)
+	GetNextFlatForModeration(ctx context.Context) (*model.Flat, error)
+	StaleModerationFlats(ctx context.Context, startedBefore time.Time, limit int64) ([]*model.Flat, error)
+	FlatPriceStats(ctx context.Context, houseID int64) ([]*model.RoomsPriceStats, error)
+	MarkHouseFlatPublished(ctx context.Context, houseID int64) error
+}
+
+type HouseRepository interface {
+	GetHouse(ctx context.Context, ID int64) (*model.House, error)
+}
+
+type FlatHistoryRepository interface {
+	SaveFlatStatusChange(ctx context.Context, change *model.FlatStatusChange) error
+	FlatStatusHistory(ctx context.Context, flatID int64) ([]*model.FlatStatusChange, error)
+	SaveFlatPriceChange(ctx context.Context, flatID, price int64) error
+	FlatPriceHistory(ctx context.Context, flatID int64) ([]*model.FlatPriceChange, error)
+	SaveFlatRuleDecisions(ctx context.Context, decisions []*model.RuleDecision) error
+	FlatRuleDecisions(ctx context.Context, flatID int64, filter model.RuleDecisionFilter) ([]*model.RuleDecision, error)
+}
+
+type FlatPhotoRepository interface {
+	SaveFlatPhoto(ctx context.Context, photo *model.FlatPhoto) (*model.FlatPhoto, error)
+	GetFlatPhoto(ctx context.Context, ID int64) (*model.FlatPhoto, error)
+	FlatPhotos(ctx context.Context, flatIDs []int64) ([]*model.FlatPhoto, error)
+	DeleteFlatPhoto(ctx context.Context, ID int64) error
+}
+
+type BlobStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+type Premoderation interface {
+	Evaluate(ctx context.Context, flat *model.Flat) ([]*model.RuleDecision, error)
 }
 
 type EventRepository interface {
@@ -17,11 +57,13 @@ type EventRepository interface {
 }
 
 type Cache interface {
-	Set(key int64, value string)
-	Get(key int64) (string, bool)
-	Remove(key int64)
+	Set(key string, value string)
+	Get(key string) (string, bool)
+	Remove(key string)
+	RemoveFunc(fn func(key string) bool)
 }
 
 type TrManager interface {
 	Do(ctx context.Context, fn func(ctx context.Context) error) (err error)
+	DoWithSettings(ctx context.Context, s trm.Settings, fn func(ctx context.Context) error) (err error)
 }
@@ -7,8 +7,11 @@ package mock_flat
 import (
 	model "avito-backend-bootcamp/internal/model"
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
+	trm "github.com/avito-tech/go-transaction-manager/trm/v2"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,19 +38,49 @@ func (m *MockFlatRepository) EXPECT() *MockFlatRepositoryMockRecorder {
 	return m.recorder
 }
 
-// FlatListByHouseID mocks base method.
-func (m *MockFlatRepository) FlatListByHouseID(ctx context.Context, houseID int64) ([]*model.Flat, error) {
+// CountFlats mocks base method.
+func (m *MockFlatRepository) CountFlats(ctx context.Context, filter model.FlatFilter) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FlatListByHouseID", ctx, houseID)
+	ret := m.ctrl.Call(m, "CountFlats", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFlats indicates an expected call of CountFlats.
+func (mr *MockFlatRepositoryMockRecorder) CountFlats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFlats", reflect.TypeOf((*MockFlatRepository)(nil).CountFlats), ctx, filter)
+}
+
+// FlatModerationQueue mocks base method.
+func (m *MockFlatRepository) FlatModerationQueue(ctx context.Context, limit int64) ([]*model.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatModerationQueue", ctx, limit)
 	ret0, _ := ret[0].([]*model.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FlatListByHouseID indicates an expected call of FlatListByHouseID.
-func (mr *MockFlatRepositoryMockRecorder) FlatListByHouseID(ctx, houseID interface{}) *gomock.Call {
+// FlatModerationQueue indicates an expected call of FlatModerationQueue.
+func (mr *MockFlatRepositoryMockRecorder) FlatModerationQueue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatModerationQueue", reflect.TypeOf((*MockFlatRepository)(nil).FlatModerationQueue), ctx, limit)
+}
+
+// FlatPriceStats mocks base method.
+func (m *MockFlatRepository) FlatPriceStats(ctx context.Context, houseID int64) ([]*model.RoomsPriceStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatPriceStats", ctx, houseID)
+	ret0, _ := ret[0].([]*model.RoomsPriceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlatPriceStats indicates an expected call of FlatPriceStats.
+func (mr *MockFlatRepositoryMockRecorder) FlatPriceStats(ctx, houseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatListByHouseID", reflect.TypeOf((*MockFlatRepository)(nil).FlatListByHouseID), ctx, houseID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatPriceStats", reflect.TypeOf((*MockFlatRepository)(nil).FlatPriceStats), ctx, houseID)
 }
 
 // GetFlat mocks base method.
@@ -65,19 +98,78 @@ func (mr *MockFlatRepositoryMockRecorder) GetFlat(ctx, ID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlat", reflect.TypeOf((*MockFlatRepository)(nil).GetFlat), ctx, ID)
 }
 
+// GetNextFlatForModeration mocks base method.
+func (m *MockFlatRepository) GetNextFlatForModeration(ctx context.Context) (*model.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextFlatForModeration", ctx)
+	ret0, _ := ret[0].(*model.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextFlatForModeration indicates an expected call of GetNextFlatForModeration.
+func (mr *MockFlatRepositoryMockRecorder) GetNextFlatForModeration(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextFlatForModeration", reflect.TypeOf((*MockFlatRepository)(nil).GetNextFlatForModeration), ctx)
+}
+
+// MarkHouseFlatPublished mocks base method.
+func (m *MockFlatRepository) MarkHouseFlatPublished(ctx context.Context, houseID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHouseFlatPublished", ctx, houseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkHouseFlatPublished indicates an expected call of MarkHouseFlatPublished.
+func (mr *MockFlatRepositoryMockRecorder) MarkHouseFlatPublished(ctx, houseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHouseFlatPublished", reflect.TypeOf((*MockFlatRepository)(nil).MarkHouseFlatPublished), ctx, houseID)
+}
+
 // SaveFlat mocks base method.
-func (m *MockFlatRepository) SaveFlat(ctx context.Context, houseID, price, fooms int64) (*model.Flat, error) {
+func (m *MockFlatRepository) SaveFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveFlat", ctx, houseID, price, fooms)
+	ret := m.ctrl.Call(m, "SaveFlat", ctx, newFlat)
 	ret0, _ := ret[0].(*model.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveFlat indicates an expected call of SaveFlat.
-func (mr *MockFlatRepositoryMockRecorder) SaveFlat(ctx, houseID, price, fooms interface{}) *gomock.Call {
+func (mr *MockFlatRepositoryMockRecorder) SaveFlat(ctx, newFlat interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlat", reflect.TypeOf((*MockFlatRepository)(nil).SaveFlat), ctx, newFlat)
+}
+
+// SearchFlats mocks base method.
+func (m *MockFlatRepository) SearchFlats(ctx context.Context, filter model.FlatFilter) ([]*model.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFlats", ctx, filter)
+	ret0, _ := ret[0].([]*model.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFlats indicates an expected call of SearchFlats.
+func (mr *MockFlatRepositoryMockRecorder) SearchFlats(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlat", reflect.TypeOf((*MockFlatRepository)(nil).SaveFlat), ctx, houseID, price, fooms)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFlats", reflect.TypeOf((*MockFlatRepository)(nil).SearchFlats), ctx, filter)
+}
+
+// StaleModerationFlats mocks base method.
+func (m *MockFlatRepository) StaleModerationFlats(ctx context.Context, startedBefore time.Time, limit int64) ([]*model.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StaleModerationFlats", ctx, startedBefore, limit)
+	ret0, _ := ret[0].([]*model.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StaleModerationFlats indicates an expected call of StaleModerationFlats.
+func (mr *MockFlatRepositoryMockRecorder) StaleModerationFlats(ctx, startedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StaleModerationFlats", reflect.TypeOf((*MockFlatRepository)(nil).StaleModerationFlats), ctx, startedBefore, limit)
 }
 
 // UpdateFlat mocks base method.
@@ -95,6 +187,339 @@ func (mr *MockFlatRepositoryMockRecorder) UpdateFlat(ctx, flat interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlat", reflect.TypeOf((*MockFlatRepository)(nil).UpdateFlat), ctx, flat)
 }
 
+// MockHouseRepository is a mock of HouseRepository interface.
+type MockHouseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHouseRepositoryMockRecorder
+}
+
+// MockHouseRepositoryMockRecorder is the mock recorder for MockHouseRepository.
+type MockHouseRepositoryMockRecorder struct {
+	mock *MockHouseRepository
+}
+
+// NewMockHouseRepository creates a new mock instance.
+func NewMockHouseRepository(ctrl *gomock.Controller) *MockHouseRepository {
+	mock := &MockHouseRepository{ctrl: ctrl}
+	mock.recorder = &MockHouseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHouseRepository) EXPECT() *MockHouseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetHouse mocks base method.
+func (m *MockHouseRepository) GetHouse(ctx context.Context, ID int64) (*model.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouse", ctx, ID)
+	ret0, _ := ret[0].(*model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouse indicates an expected call of GetHouse.
+func (mr *MockHouseRepositoryMockRecorder) GetHouse(ctx, ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouse", reflect.TypeOf((*MockHouseRepository)(nil).GetHouse), ctx, ID)
+}
+
+// MockFlatHistoryRepository is a mock of FlatHistoryRepository interface.
+type MockFlatHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatHistoryRepositoryMockRecorder
+}
+
+// MockFlatHistoryRepositoryMockRecorder is the mock recorder for MockFlatHistoryRepository.
+type MockFlatHistoryRepositoryMockRecorder struct {
+	mock *MockFlatHistoryRepository
+}
+
+// NewMockFlatHistoryRepository creates a new mock instance.
+func NewMockFlatHistoryRepository(ctrl *gomock.Controller) *MockFlatHistoryRepository {
+	mock := &MockFlatHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockFlatHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatHistoryRepository) EXPECT() *MockFlatHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FlatPriceHistory mocks base method.
+func (m *MockFlatHistoryRepository) FlatPriceHistory(ctx context.Context, flatID int64) ([]*model.FlatPriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatPriceHistory", ctx, flatID)
+	ret0, _ := ret[0].([]*model.FlatPriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlatPriceHistory indicates an expected call of FlatPriceHistory.
+func (mr *MockFlatHistoryRepositoryMockRecorder) FlatPriceHistory(ctx, flatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatPriceHistory", reflect.TypeOf((*MockFlatHistoryRepository)(nil).FlatPriceHistory), ctx, flatID)
+}
+
+// FlatRuleDecisions mocks base method.
+func (m *MockFlatHistoryRepository) FlatRuleDecisions(ctx context.Context, flatID int64, filter model.RuleDecisionFilter) ([]*model.RuleDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatRuleDecisions", ctx, flatID, filter)
+	ret0, _ := ret[0].([]*model.RuleDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlatRuleDecisions indicates an expected call of FlatRuleDecisions.
+func (mr *MockFlatHistoryRepositoryMockRecorder) FlatRuleDecisions(ctx, flatID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatRuleDecisions", reflect.TypeOf((*MockFlatHistoryRepository)(nil).FlatRuleDecisions), ctx, flatID, filter)
+}
+
+// FlatStatusHistory mocks base method.
+func (m *MockFlatHistoryRepository) FlatStatusHistory(ctx context.Context, flatID int64) ([]*model.FlatStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatStatusHistory", ctx, flatID)
+	ret0, _ := ret[0].([]*model.FlatStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlatStatusHistory indicates an expected call of FlatStatusHistory.
+func (mr *MockFlatHistoryRepositoryMockRecorder) FlatStatusHistory(ctx, flatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatStatusHistory", reflect.TypeOf((*MockFlatHistoryRepository)(nil).FlatStatusHistory), ctx, flatID)
+}
+
+// SaveFlatPriceChange mocks base method.
+func (m *MockFlatHistoryRepository) SaveFlatPriceChange(ctx context.Context, flatID, price int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFlatPriceChange", ctx, flatID, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFlatPriceChange indicates an expected call of SaveFlatPriceChange.
+func (mr *MockFlatHistoryRepositoryMockRecorder) SaveFlatPriceChange(ctx, flatID, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlatPriceChange", reflect.TypeOf((*MockFlatHistoryRepository)(nil).SaveFlatPriceChange), ctx, flatID, price)
+}
+
+// SaveFlatRuleDecisions mocks base method.
+func (m *MockFlatHistoryRepository) SaveFlatRuleDecisions(ctx context.Context, decisions []*model.RuleDecision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFlatRuleDecisions", ctx, decisions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFlatRuleDecisions indicates an expected call of SaveFlatRuleDecisions.
+func (mr *MockFlatHistoryRepositoryMockRecorder) SaveFlatRuleDecisions(ctx, decisions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlatRuleDecisions", reflect.TypeOf((*MockFlatHistoryRepository)(nil).SaveFlatRuleDecisions), ctx, decisions)
+}
+
+// SaveFlatStatusChange mocks base method.
+func (m *MockFlatHistoryRepository) SaveFlatStatusChange(ctx context.Context, change *model.FlatStatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFlatStatusChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFlatStatusChange indicates an expected call of SaveFlatStatusChange.
+func (mr *MockFlatHistoryRepositoryMockRecorder) SaveFlatStatusChange(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlatStatusChange", reflect.TypeOf((*MockFlatHistoryRepository)(nil).SaveFlatStatusChange), ctx, change)
+}
+
+// MockFlatPhotoRepository is a mock of FlatPhotoRepository interface.
+type MockFlatPhotoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatPhotoRepositoryMockRecorder
+}
+
+// MockFlatPhotoRepositoryMockRecorder is the mock recorder for MockFlatPhotoRepository.
+type MockFlatPhotoRepositoryMockRecorder struct {
+	mock *MockFlatPhotoRepository
+}
+
+// NewMockFlatPhotoRepository creates a new mock instance.
+func NewMockFlatPhotoRepository(ctrl *gomock.Controller) *MockFlatPhotoRepository {
+	mock := &MockFlatPhotoRepository{ctrl: ctrl}
+	mock.recorder = &MockFlatPhotoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatPhotoRepository) EXPECT() *MockFlatPhotoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteFlatPhoto mocks base method.
+func (m *MockFlatPhotoRepository) DeleteFlatPhoto(ctx context.Context, ID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFlatPhoto", ctx, ID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFlatPhoto indicates an expected call of DeleteFlatPhoto.
+func (mr *MockFlatPhotoRepositoryMockRecorder) DeleteFlatPhoto(ctx, ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFlatPhoto", reflect.TypeOf((*MockFlatPhotoRepository)(nil).DeleteFlatPhoto), ctx, ID)
+}
+
+// FlatPhotos mocks base method.
+func (m *MockFlatPhotoRepository) FlatPhotos(ctx context.Context, flatIDs []int64) ([]*model.FlatPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlatPhotos", ctx, flatIDs)
+	ret0, _ := ret[0].([]*model.FlatPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlatPhotos indicates an expected call of FlatPhotos.
+func (mr *MockFlatPhotoRepositoryMockRecorder) FlatPhotos(ctx, flatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlatPhotos", reflect.TypeOf((*MockFlatPhotoRepository)(nil).FlatPhotos), ctx, flatIDs)
+}
+
+// GetFlatPhoto mocks base method.
+func (m *MockFlatPhotoRepository) GetFlatPhoto(ctx context.Context, ID int64) (*model.FlatPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFlatPhoto", ctx, ID)
+	ret0, _ := ret[0].(*model.FlatPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlatPhoto indicates an expected call of GetFlatPhoto.
+func (mr *MockFlatPhotoRepositoryMockRecorder) GetFlatPhoto(ctx, ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatPhoto", reflect.TypeOf((*MockFlatPhotoRepository)(nil).GetFlatPhoto), ctx, ID)
+}
+
+// SaveFlatPhoto mocks base method.
+func (m *MockFlatPhotoRepository) SaveFlatPhoto(ctx context.Context, photo *model.FlatPhoto) (*model.FlatPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFlatPhoto", ctx, photo)
+	ret0, _ := ret[0].(*model.FlatPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveFlatPhoto indicates an expected call of SaveFlatPhoto.
+func (mr *MockFlatPhotoRepositoryMockRecorder) SaveFlatPhoto(ctx, photo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFlatPhoto", reflect.TypeOf((*MockFlatPhotoRepository)(nil).SaveFlatPhoto), ctx, photo)
+}
+
+// MockBlobStorage is a mock of BlobStorage interface.
+type MockBlobStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStorageMockRecorder
+}
+
+// MockBlobStorageMockRecorder is the mock recorder for MockBlobStorage.
+type MockBlobStorageMockRecorder struct {
+	mock *MockBlobStorage
+}
+
+// NewMockBlobStorage creates a new mock instance.
+func NewMockBlobStorage(ctrl *gomock.Controller) *MockBlobStorage {
+	mock := &MockBlobStorage{ctrl: ctrl}
+	mock.recorder = &MockBlobStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStorage) EXPECT() *MockBlobStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStorage)(nil).Delete), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStorageMockRecorder) Put(ctx, key, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStorage)(nil).Put), ctx, key, r)
+}
+
+// URL mocks base method.
+func (m *MockBlobStorage) URL(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockBlobStorageMockRecorder) URL(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockBlobStorage)(nil).URL), key)
+}
+
+// MockPremoderation is a mock of Premoderation interface.
+type MockPremoderation struct {
+	ctrl     *gomock.Controller
+	recorder *MockPremoderationMockRecorder
+}
+
+// MockPremoderationMockRecorder is the mock recorder for MockPremoderation.
+type MockPremoderationMockRecorder struct {
+	mock *MockPremoderation
+}
+
+// NewMockPremoderation creates a new mock instance.
+func NewMockPremoderation(ctrl *gomock.Controller) *MockPremoderation {
+	mock := &MockPremoderation{ctrl: ctrl}
+	mock.recorder = &MockPremoderationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPremoderation) EXPECT() *MockPremoderationMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockPremoderation) Evaluate(ctx context.Context, flat *model.Flat) ([]*model.RuleDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", ctx, flat)
+	ret0, _ := ret[0].([]*model.RuleDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockPremoderationMockRecorder) Evaluate(ctx, flat interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockPremoderation)(nil).Evaluate), ctx, flat)
+}
+
 // MockEventRepository is a mock of EventRepository interface.
 type MockEventRepository struct {
 	ctrl     *gomock.Controller
@@ -156,7 +581,7 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockCache) Get(key int64) (string, bool) {
+func (m *MockCache) Get(key string) (string, bool) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", key)
 	ret0, _ := ret[0].(string)
@@ -171,7 +596,7 @@ func (mr *MockCacheMockRecorder) Get(key interface{}) *gomock.Call {
 }
 
 // Remove mocks base method.
-func (m *MockCache) Remove(key int64) {
+func (m *MockCache) Remove(key string) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "Remove", key)
 }
@@ -182,8 +607,20 @@ func (mr *MockCacheMockRecorder) Remove(key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockCache)(nil).Remove), key)
 }
 
+// RemoveFunc mocks base method.
+func (m *MockCache) RemoveFunc(fn func(string) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveFunc", fn)
+}
+
+// RemoveFunc indicates an expected call of RemoveFunc.
+func (mr *MockCacheMockRecorder) RemoveFunc(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFunc", reflect.TypeOf((*MockCache)(nil).RemoveFunc), fn)
+}
+
 // Set mocks base method.
-func (m *MockCache) Set(key int64, value string) {
+func (m *MockCache) Set(key, value string) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "Set", key, value)
 }
@@ -230,3 +667,17 @@ func (mr *MockTrManagerMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTrManager)(nil).Do), ctx, fn)
 }
+
+// DoWithSettings mocks base method.
+func (m *MockTrManager) DoWithSettings(ctx context.Context, s trm.Settings, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoWithSettings", ctx, s, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DoWithSettings indicates an expected call of DoWithSettings.
+func (mr *MockTrManagerMockRecorder) DoWithSettings(ctx, s, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoWithSettings", reflect.TypeOf((*MockTrManager)(nil).DoWithSettings), ctx, s, fn)
+}
@@ -0,0 +1,107 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// releaseBatchSize limits the number of flats released in one transaction.
+const releaseBatchSize = 100
+
+// StartReleaseModerations starts a goroutine that periodically returns flats
+// stuck on moderation for longer than the timeout to the moderation queue.
+func (s *Service) StartReleaseModerations(ctx context.Context, checkPeriod, timeout time.Duration) {
+	const op = "flat.StartReleaseModerations"
+
+	log := s.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(checkPeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("stopping moderation release")
+				return
+			case <-ticker.C:
+				// Release stale moderations
+				_, err := s.ReleaseStaleModerations(ctx, timeout)
+				if err != nil {
+					log.Error("failed to release stale moderations", sl.Err(err))
+				}
+			}
+		}
+	}()
+}
+
+// ReleaseStaleModerations returns flats that have been on moderation for longer
+// than the timeout to created status on behalf of the system, recording
+// the timeout as the reason of the transition. It returns the released flats.
+func (s *Service) ReleaseStaleModerations(ctx context.Context, timeout time.Duration) (flats []*model.Flat, err error) {
+	const op = "flat.ReleaseStaleModerations"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Duration("timeout", timeout),
+	)
+
+	reason := fmt.Sprintf("moderation timed out after %s", timeout)
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		flats, err = s.flatRepository.StaleModerationFlats(ctx, time.Now().Add(-timeout), releaseBatchSize)
+		if err != nil {
+			log.Error("failed to get stale moderations", sl.Err(err))
+			return err
+		}
+
+		for i, flat := range flats {
+			oldStatus := flat.Status
+			transition, err := flat.ChangeStatus(model.FlatStatusUpdate{Status: model.StatusCreated}, model.SystemActor)
+			if err != nil {
+				log.Error("failed to change status", slog.Int64("flat_id", flat.ID), sl.Err(err))
+				return err
+			}
+
+			flats[i], err = s.updateFlat(ctx, flat)
+			if err != nil {
+				log.Error("failed to update flat in db", slog.Int64("flat_id", flat.ID), sl.Err(err))
+				return err
+			}
+
+			err = s.historyRepository.SaveFlatStatusChange(ctx, &model.FlatStatusChange{
+				FlatID:    flat.ID,
+				OldStatus: oldStatus,
+				NewStatus: flat.Status,
+				ActorID:   model.SystemActor.ID,
+				ActorRole: model.SystemActor.Role,
+				Reason:    &reason,
+			})
+			if err != nil {
+				log.Error("failed to save status change", slog.Int64("flat_id", flat.ID), sl.Err(err))
+				return err
+			}
+
+			err = s.applyTransitionEffects(ctx, flats[i], transition)
+			if err != nil {
+				log.Error("failed to apply transition effects", slog.Int64("flat_id", flat.ID), sl.Err(err))
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(flats) > 0 {
+		log.Info("released stale moderations", slog.Int("count", len(flats)))
+	}
+
+	return flats, nil
+}
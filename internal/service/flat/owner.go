@@ -0,0 +1,144 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// GetFlat retrieves a single flat. Approved flats are visible to everyone,
+// other flats only to their owner and moderators.
+func (s *Service) GetFlat(ctx context.Context, ID int64, actor model.Actor) (*model.Flat, error) {
+	const op = "flat.GetFlat"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", ID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	flat, err := s.flatRepository.GetFlat(ctx, ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("flat does not exist", sl.Err(err))
+			return nil, ErrFlatNotExist
+		}
+		log.Error("failed to find flat", sl.Err(err))
+		return nil, err
+	}
+
+	// hide the existence of non-approved flats from other users
+	if !canSeeFlat(flat, actor) {
+		log.Error("attempt to see foreign non-approved flat")
+		return nil, ErrFlatNotExist
+	}
+
+	err = s.fillPhotos(ctx, []*model.Flat{flat})
+	if err != nil {
+		log.Error("failed to get flat photos", sl.Err(err))
+		return nil, err
+	}
+
+	flat.FillPriceChange()
+	return flat, nil
+}
+
+func canSeeFlat(flat *model.Flat, actor model.Actor) bool {
+	return flat.Status == model.StatusApproved ||
+		actor.Role == model.Moderator ||
+		flat.OwnedBy(actor.ID)
+}
+
+// OwnerFlats retrieves a page of flats created by the actor in every status.
+func (s *Service) OwnerFlats(ctx context.Context, owner model.Actor, filter model.FlatFilter) (*model.FlatPage, error) {
+	const op = "flat.OwnerFlats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("owner_id", owner.ID.String()),
+	)
+
+	filter = normalizeFilter(filter)
+	filter.OwnerID = owner.ID
+
+	page, err := s.flatPageWithTotal(ctx, filter)
+	if err != nil {
+		log.Error("failed to get owner flats", sl.Err(err))
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// CloseFlat withdraws the flat from the catalogue on behalf of its owner,
+// either archiving it or marking it as sold. Subscribers of the house are
+// notified when an approved flat leaves the catalogue.
+func (s *Service) CloseFlat(ctx context.Context, ID int64, status model.FlatStatus, actor model.Actor) (flat *model.Flat, err error) {
+	const op = "flat.CloseFlat"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", ID),
+		slog.String("status", string(status)),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	flat, err = s.flatRepository.GetFlat(ctx, ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("flat does not exist", sl.Err(err))
+			return nil, ErrFlatNotExist
+		}
+		log.Error("failed to find flat", sl.Err(err))
+		return nil, err
+	}
+
+	if !flat.OwnedBy(actor.ID) {
+		log.Error("attempt to close foreign flat")
+		return nil, ErrNotFlatOwner
+	}
+
+	if status != model.StatusArchived && status != model.StatusSold {
+		log.Error("not a closing status")
+		return nil, model.ErrImpossibleTransition
+	}
+
+	oldStatus := flat.Status
+	transition, err := flat.ChangeStatus(model.FlatStatusUpdate{Status: status}, actor)
+	if err != nil {
+		log.Error("failed to change status", sl.Err(err))
+		return nil, err
+	}
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		flat, err = s.updateFlat(ctx, flat)
+		if err != nil {
+			log.Error("failed to update flat in db", sl.Err(err))
+			return err
+		}
+
+		err = s.saveStatusChange(ctx, flat, oldStatus, actor)
+		if err != nil {
+			log.Error("failed to save status change", sl.Err(err))
+			return err
+		}
+
+		err = s.applyTransitionEffects(ctx, flat, transition)
+		if err != nil {
+			log.Error("failed to apply transition effects", sl.Err(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Error("failed to close flat", sl.Err(err))
+		return nil, err
+	}
+
+	return flat, nil
+}
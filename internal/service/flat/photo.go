@@ -0,0 +1,314 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"avito-backend-bootcamp/pkg/utils/thumbnail"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxPhotoSize is the largest photo accepted for upload, in bytes.
+	MaxPhotoSize = 10 << 20
+
+	// maxPhotoPixels protects from decoding images that are small on disk
+	// but take a lot of memory once unpacked.
+	maxPhotoPixels = 50_000_000
+
+	thumbnailSide    = 320
+	thumbnailQuality = 80
+)
+
+// photoExtensions lists the supported photo types along with their file extensions.
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+var (
+	ErrPhotoTooLarge     = fmt.Errorf("photo must not exceed %d MB", MaxPhotoSize>>20)
+	ErrUnsupportedPhoto  = errors.New("photo must be a JPEG or PNG image")
+	ErrFlatPhotoNotExist = errors.New("this photo does not exist")
+)
+
+// CheckPhoto makes sure the photo fits the size limit and is of a supported type,
+// returning the content type detected from its data.
+func CheckPhoto(data []byte) (string, error) {
+	if len(data) > MaxPhotoSize {
+		return "", ErrPhotoTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := photoExtensions[contentType]; !ok {
+		return contentType, ErrUnsupportedPhoto
+	}
+
+	return contentType, nil
+}
+
+// UploadFlatPhoto stores a new photo of the flat along with its thumbnail.
+// Approved and declined flats are returned to moderation.
+func (s *Service) UploadFlatPhoto(ctx context.Context, flatID int64, data []byte, actor model.Actor) (*model.FlatPhoto, error) {
+	const op = "flat.UploadFlatPhoto"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+		slog.Int("size", len(data)),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	contentType, err := CheckPhoto(data)
+	if err != nil {
+		log.Error("invalid photo", slog.String("content_type", contentType), sl.Err(err))
+		return nil, err
+	}
+	ext := photoExtensions[contentType]
+
+	thumb, err := makeThumbnail(data)
+	if err != nil {
+		log.Error("failed to make thumbnail", sl.Err(err))
+		return nil, ErrUnsupportedPhoto
+	}
+
+	flat, err := s.ownedFlat(ctx, flatID, actor)
+	if err != nil {
+		log.Error("failed to get flat", sl.Err(err))
+		return nil, err
+	}
+
+	oldStatus := flat.Status
+	transition, err := flat.AddPhoto(actor)
+	if err != nil {
+		log.Error("failed to add photo", sl.Err(err))
+		return nil, err
+	}
+
+	// store the files first, the database rows only reference them
+	name := uuid.New().String()
+	photo := &model.FlatPhoto{
+		FlatID:       flat.ID,
+		Key:          fmt.Sprintf("flats/%d/%s%s", flat.ID, name, ext),
+		ThumbnailKey: fmt.Sprintf("flats/%d/%s_thumb.jpg", flat.ID, name),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+	}
+
+	err = s.storage.Put(ctx, photo.Key, bytes.NewReader(data))
+	if err != nil {
+		log.Error("failed to store photo", sl.Err(err))
+		return nil, err
+	}
+
+	err = s.storage.Put(ctx, photo.ThumbnailKey, bytes.NewReader(thumb))
+	if err != nil {
+		log.Error("failed to store thumbnail", sl.Err(err))
+		s.deletePhotoFiles(ctx, log, photo)
+		return nil, err
+	}
+
+	var saved *model.FlatPhoto
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		saved, err = s.photoRepository.SaveFlatPhoto(ctx, photo)
+		if err != nil {
+			log.Error("failed to save photo in db", sl.Err(err))
+			return err
+		}
+
+		if flat.Status == oldStatus {
+			return nil
+		}
+
+		flat, err = s.updateFlat(ctx, flat)
+		if err != nil {
+			log.Error("failed to update flat in db", sl.Err(err))
+			return err
+		}
+
+		err = s.saveStatusChange(ctx, flat, oldStatus, actor)
+		if err != nil {
+			log.Error("failed to save status change", sl.Err(err))
+			return err
+		}
+
+		err = s.applyTransitionEffects(ctx, flat, transition)
+		if err != nil {
+			log.Error("failed to apply transition effects", sl.Err(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Error("failed to upload photo", sl.Err(err))
+		s.deletePhotoFiles(ctx, log, photo)
+		return nil, err
+	}
+
+	s.fillPhotoURLs(saved)
+	return saved, nil
+}
+
+// GetFlatPhotos retrieves photos of the flat visible to the actor.
+func (s *Service) GetFlatPhotos(ctx context.Context, flatID int64, actor model.Actor) ([]*model.FlatPhoto, error) {
+	flat, err := s.GetFlat(ctx, flatID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if flat.Photos == nil {
+		return []*model.FlatPhoto{}, nil
+	}
+
+	return flat.Photos, nil
+}
+
+// DeleteFlatPhoto removes the photo of the flat along with its files.
+func (s *Service) DeleteFlatPhoto(ctx context.Context, flatID int64, photoID int64, actor model.Actor) error {
+	const op = "flat.DeleteFlatPhoto"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+		slog.Int64("photo_id", photoID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	flat, err := s.ownedFlat(ctx, flatID, actor)
+	if err != nil {
+		log.Error("failed to get flat", sl.Err(err))
+		return err
+	}
+
+	err = flat.RemovePhoto()
+	if err != nil {
+		log.Error("failed to remove photo", sl.Err(err))
+		return err
+	}
+
+	photo, err := s.photoRepository.GetFlatPhoto(ctx, photoID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("photo does not exist", sl.Err(err))
+			return ErrFlatPhotoNotExist
+		}
+		log.Error("failed to find photo", sl.Err(err))
+		return err
+	}
+
+	if photo.FlatID != flat.ID {
+		log.Error("photo belongs to another flat")
+		return ErrFlatPhotoNotExist
+	}
+
+	err = s.photoRepository.DeleteFlatPhoto(ctx, photo.ID)
+	if err != nil {
+		log.Error("failed to delete photo from db", sl.Err(err))
+		return err
+	}
+
+	s.deletePhotoFiles(ctx, log, photo)
+
+	if flat.Status == model.StatusApproved {
+		s.invalidateHouseCache(flat.HouseID)
+	}
+
+	return nil
+}
+
+// ownedFlat retrieves the flat, making sure it belongs to the actor.
+func (s *Service) ownedFlat(ctx context.Context, flatID int64, actor model.Actor) (*model.Flat, error) {
+	flat, err := s.flatRepository.GetFlat(ctx, flatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrFlatNotExist
+		}
+		return nil, err
+	}
+
+	if !flat.OwnedBy(actor.ID) {
+		return nil, ErrNotFlatOwner
+	}
+
+	return flat, nil
+}
+
+// fillPhotos attaches photos to the flats.
+func (s *Service) fillPhotos(ctx context.Context, flats []*model.Flat) error {
+	if len(flats) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*model.Flat, len(flats))
+	flatIDs := make([]int64, 0, len(flats))
+	for _, flat := range flats {
+		flat.Photos = nil
+		byID[flat.ID] = flat
+		flatIDs = append(flatIDs, flat.ID)
+	}
+
+	photos, err := s.photoRepository.FlatPhotos(ctx, flatIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, photo := range photos {
+		s.fillPhotoURLs(photo)
+		if flat, ok := byID[photo.FlatID]; ok {
+			flat.Photos = append(flat.Photos, photo)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) fillPhotoURLs(photo *model.FlatPhoto) {
+	photo.URL = s.storage.URL(photo.Key)
+	photo.ThumbnailURL = s.storage.URL(photo.ThumbnailKey)
+}
+
+// deletePhotoFiles removes the files of the photo. Failures are only logged
+// since the files are no longer referenced.
+func (s *Service) deletePhotoFiles(ctx context.Context, log *slog.Logger, photo *model.FlatPhoto) {
+	for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Error("failed to delete photo file", slog.String("key", key), sl.Err(err))
+		}
+	}
+}
+
+// makeThumbnail decodes the photo and encodes its downscaled copy as JPEG.
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return nil, fmt.Errorf("photo is %dx%d pixels", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, thumbnail.Resize(img, thumbnailSide), &jpeg.Options{Quality: thumbnailQuality})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
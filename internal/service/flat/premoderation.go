@@ -0,0 +1,74 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+)
+
+// premoderate applies the premoderation rules to the created flat and logs
+// their decisions. A declining rule declines the flat on behalf of the system,
+// a flagging rule marks the flat for the moderator. It is expected to run
+// in the transaction that saves the flat.
+func (s *Service) premoderate(ctx context.Context, flat *model.Flat) (*model.Flat, error) {
+	decisions, err := s.premoderation.Evaluate(ctx, flat)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.historyRepository.SaveFlatRuleDecisions(ctx, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		decline *model.RuleDecision
+		flagged bool
+	)
+	for _, decision := range decisions {
+		switch decision.Action {
+		case model.RuleActionDecline:
+			if decline == nil {
+				decline = decision
+			}
+		case model.RuleActionFlag:
+			flagged = true
+		}
+	}
+
+	if decline == nil && flagged == flat.Flagged {
+		return flat, nil
+	}
+
+	oldStatus := flat.Status
+	flat.Flagged = flagged
+	var transition model.FlatTransition
+	if decline != nil {
+		update := model.FlatStatusUpdate{Status: model.StatusDeclined, Reason: *decline.Reason}
+		if decline.Details != nil {
+			update.Comment = *decline.Details
+		}
+		transition, err = flat.ChangeStatus(update, model.SystemActor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	flat, err = s.updateFlat(ctx, flat)
+	if err != nil {
+		return nil, err
+	}
+
+	if flat.Status != oldStatus {
+		err = s.saveStatusChange(ctx, flat, oldStatus, model.SystemActor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.applyTransitionEffects(ctx, flat, transition)
+	if err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
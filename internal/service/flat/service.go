@@ -7,66 +7,133 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 )
 
 type Service struct {
-	log             *slog.Logger
-	flatRepository  FlatRepository
-	eventRepository EventRepository
-	cache           Cache
-	trManager       TrManager
+	log               *slog.Logger
+	flatRepository    FlatRepository
+	houseRepository   HouseRepository
+	historyRepository FlatHistoryRepository
+	photoRepository   FlatPhotoRepository
+	eventRepository   EventRepository
+	storage           BlobStorage
+	premoderation     Premoderation
+	cache             Cache
+	trManager         TrManager
 }
 
 func New(
 	log *slog.Logger,
 	flatRepository FlatRepository,
+	houseRepository HouseRepository,
+	historyRepository FlatHistoryRepository,
+	photoRepository FlatPhotoRepository,
 	eventRepository EventRepository,
+	storage BlobStorage,
+	premoderation Premoderation,
 	cache Cache,
 	trManager TrManager,
 ) *Service {
 	return &Service{
-		log:             log,
-		flatRepository:  flatRepository,
-		eventRepository: eventRepository,
-		cache:           cache,
-		trManager:       trManager,
+		log:               log,
+		flatRepository:    flatRepository,
+		houseRepository:   houseRepository,
+		historyRepository: historyRepository,
+		photoRepository:   photoRepository,
+		eventRepository:   eventRepository,
+		storage:           storage,
+		premoderation:     premoderation,
+		cache:             cache,
+		trManager:         trManager,
 	}
 }
 
-var ErrHouseNotExist = errors.New("failed to create flat in non-exist house")
+var (
+	ErrHouseNotExist   = errors.New("house does not exist")
+	ErrFlatNumberTaken = errors.New("flat with this number already exists in the house")
+)
 
-func (s *Service) CreateFlat(ctx context.Context, houseID, price, rooms int64) (*model.Flat, error) {
+func (s *Service) CreateFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error) {
 	const op = "flat.UpdateCreateFlatFlat"
 
 	log := s.log.With(
 		slog.String("op", op),
-		slog.Int64("houseID", houseID),
-		slog.Int64("price", price),
-		slog.Int64("rooms", rooms),
+		slog.Int64("houseID", newFlat.HouseID),
+		slog.Int64("number", newFlat.Number),
+		slog.Int64("price", newFlat.Price),
+		slog.Int64("rooms", newFlat.Rooms),
+		slog.String("owner_id", newFlat.OwnerID.String()),
 	)
 
-	flat, err := s.flatRepository.SaveFlat(ctx, houseID, price, rooms)
+	var flat *model.Flat
+	err := s.trManager.Do(ctx, func(ctx context.Context) (err error) {
+		flat, err = s.saveFlat(ctx, newFlat)
+		return err
+	})
 	if err != nil {
 		log.Error("failed to save flat", sl.Err(err))
-		if errors.Is(err, repository.ErrConstraintViolation) {
-			return nil, ErrHouseNotExist
-		}
-		return nil, err
+		return nil, saveFlatError(err)
 	}
 
 	return flat, nil
 }
 
-var ErrFlatNotExist = errors.New("this flat does not exist")
+// saveFlatError translates repository errors of a flat insert.
+func saveFlatError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrConstraintViolation):
+		return ErrHouseNotExist
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return ErrFlatNumberTaken
+	default:
+		return err
+	}
+}
 
-func (s *Service) UpdateFlat(ctx context.Context, ID int64, status model.FlatStatus) (flat *model.Flat, err error) {
+// saveFlat checks the attributes of a new flat, saves it along with the first record
+// of its price history and applies the premoderation rules to it.
+// It is expected to run in a transaction.
+func (s *Service) saveFlat(ctx context.Context, newFlat model.NewFlat) (*model.Flat, error) {
+	err := newFlat.FlatAttributes.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := s.flatRepository.SaveFlat(ctx, newFlat)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.historyRepository.SaveFlatPriceChange(ctx, flat.ID, flat.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.premoderate(ctx, flat)
+}
+
+var (
+	ErrFlatNotExist = errors.New("this flat does not exist")
+	ErrFlatModified = errors.New("flat was modified concurrently, reload it and retry")
+)
+
+// updateFlat saves the flat read earlier, failing if it has been changed since.
+func (s *Service) updateFlat(ctx context.Context, flat *model.Flat) (*model.Flat, error) {
+	flat, err := s.flatRepository.UpdateFlat(ctx, flat)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrFlatModified
+	}
+	return flat, err
+}
+
+func (s *Service) UpdateFlat(ctx context.Context, ID int64, update model.FlatStatusUpdate, actor model.Actor) (flat *model.Flat, err error) {
 	const op = "flat.UpdateFlat"
 
 	log := s.log.With(
 		slog.String("op", op),
-		slog.String("status", string(status)),
+		slog.String("status", string(update.Status)),
+		slog.String("actor_id", actor.ID.String()),
 	)
 
 	flat, err = s.flatRepository.GetFlat(ctx, ID)
@@ -79,59 +146,211 @@ func (s *Service) UpdateFlat(ctx context.Context, ID int64, status model.FlatSta
 		return nil, err
 	}
 
-	switch status {
-	case model.StatusApproved:
-		err = flat.Approve()
-	case model.StatusDeclined:
-		err = flat.Decline()
-	case model.StatusOnModeration:
-		err = flat.StartModeration()
-	default:
-		return nil, model.ErrImpossibleTransition
+	if update.Version != 0 && update.Version != flat.Version {
+		log.Error("stale flat version", slog.Int64("version", update.Version), slog.Int64("current_version", flat.Version))
+		return nil, ErrFlatModified
 	}
+
+	oldStatus := flat.Status
+	transition, err := flat.ChangeStatus(update, actor)
 	if err != nil {
 		log.Error("failed to change status", sl.Err(err))
 		return nil, err
 	}
 
 	err = s.trManager.Do(ctx, func(ctx context.Context) error {
-		flat, err = s.flatRepository.UpdateFlat(ctx, flat)
+		flat, err = s.updateFlat(ctx, flat)
 		if err != nil {
 			log.Error("failed to update flat in db", sl.Err(err))
 			return err
 		}
 
-		if flat.Status == model.StatusApproved {
-			eventPayload := fmt.Sprintf(
-				`{"house_id": %d}`,
-				flat.HouseID,
-			)
+		err = s.saveStatusChange(ctx, flat, oldStatus, actor)
+		if err != nil {
+			log.Error("failed to save status change", sl.Err(err))
+			return err
+		}
+
+		err = s.applyTransitionEffects(ctx, flat, transition)
+		if err != nil {
+			log.Error("failed to apply transition effects", sl.Err(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Error("failed to update flat and publish event", sl.Err(err))
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+var ErrNotFlatOwner = errors.New("only the owner can change this flat")
+
+// EditFlat changes the flat parameters on behalf of its owner. Approved and
+// declined flats are returned to created status to pass the moderation again.
+func (s *Service) EditFlat(ctx context.Context, ID int64, edit model.FlatEdit, actor model.Actor) (flat *model.Flat, err error) {
+	const op = "flat.EditFlat"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", ID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	flat, err = s.flatRepository.GetFlat(ctx, ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("flat does not exist", sl.Err(err))
+			return nil, ErrFlatNotExist
+		}
+		log.Error("failed to find flat", sl.Err(err))
+		return nil, err
+	}
+
+	if !flat.OwnedBy(actor.ID) {
+		log.Error("attempt to edit foreign flat")
+		return nil, ErrNotFlatOwner
+	}
+
+	oldStatus, oldPrice := flat.Status, flat.Price
+	transition, err := flat.Edit(edit, actor)
+	if err != nil {
+		log.Error("failed to edit flat", sl.Err(err))
+		return nil, err
+	}
 
-			// it is necessary to invalidate the cache
-			// since flat status was updated to approve
-			s.cache.Remove(flat.HouseID)
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		flat, err = s.updateFlat(ctx, flat)
+		if err != nil {
+			log.Error("failed to update flat in db", sl.Err(err))
+			return err
+		}
 
-			err = s.eventRepository.PublishEvent(ctx, model.FlatApproved, eventPayload)
+		if flat.Status != oldStatus {
+			err = s.saveStatusChange(ctx, flat, oldStatus, actor)
 			if err != nil {
-				log.Error("fauled to publish event", sl.Err(err))
+				log.Error("failed to save status change", sl.Err(err))
 				return err
 			}
 		}
 
+		if flat.Price != oldPrice {
+			err = s.historyRepository.SaveFlatPriceChange(ctx, flat.ID, flat.Price)
+			if err != nil {
+				log.Error("failed to save price change", sl.Err(err))
+				return err
+			}
+		}
+
+		flat, err = s.premoderate(ctx, flat)
+		if err != nil {
+			log.Error("failed to premoderate flat", sl.Err(err))
+			return err
+		}
+
+		err = s.applyTransitionEffects(ctx, flat, transition)
+		if err != nil {
+			log.Error("failed to apply transition effects", sl.Err(err))
+			return err
+		}
+
 		return nil
 	})
 
 	if err != nil {
-		log.Error("failed to update flat and publish event", sl.Err(err))
+		log.Error("failed to edit flat", sl.Err(err))
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+// ModerationQueue retrieves flats waiting for moderation, oldest first.
+func (s *Service) ModerationQueue(ctx context.Context, limit int64) ([]*model.Flat, error) {
+	const op = "flat.ModerationQueue"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("limit", limit),
+	)
+
+	if limit <= 0 || limit > maxPageSize {
+		limit = defaultPageSize
+	}
+
+	flatList, err := s.flatRepository.FlatModerationQueue(ctx, limit)
+	if err != nil {
+		log.Error("failed to get moderation queue", sl.Err(err))
+		return nil, err
+	}
+
+	if len(flatList) == 0 {
+		return []*model.Flat{}, nil
+	}
+
+	return flatList, nil
+}
+
+var ErrModerationQueueEmpty = errors.New("there are no flats waiting for moderation")
+
+// ClaimFlat atomically takes the oldest flat waiting for moderation and starts
+// its moderation. Row locking guarantees that concurrent moderators never
+// claim the same flat.
+func (s *Service) ClaimFlat(ctx context.Context, moderator model.Actor) (flat *model.Flat, err error) {
+	const op = "flat.ClaimFlat"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("moderator_id", moderator.ID.String()),
+	)
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		flat, err = s.flatRepository.GetNextFlatForModeration(ctx)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrModerationQueueEmpty
+			}
+			log.Error("failed to get next flat for moderation", sl.Err(err))
+			return err
+		}
+
+		oldStatus := flat.Status
+		_, err = flat.ChangeStatus(model.FlatStatusUpdate{Status: model.StatusOnModeration}, moderator)
+		if err != nil {
+			log.Error("failed to change status", sl.Err(err))
+			return err
+		}
+
+		flat, err = s.updateFlat(ctx, flat)
+		if err != nil {
+			log.Error("failed to update flat in db", sl.Err(err))
+			return err
+		}
+
+		err = s.saveStatusChange(ctx, flat, oldStatus, moderator)
+		if err != nil {
+			log.Error("failed to save status change", sl.Err(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Error("failed to claim flat", sl.Err(err))
 		return nil, err
 	}
 
 	return flat, nil
 }
 
-// GetFlatListByHouseID retrieves a list of flats for a given house ID,
+// GetFlatListByHouseID retrieves a page of flats for a given house ID,
 // applying visibility rules based on the user role.
-func (s *Service) GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType) (flatList []*model.Flat, err error) {
+func (s *Service) GetFlatListByHouseID(ctx context.Context, houseID int64, userRole model.UserType, filter model.FlatFilter) (page *model.FlatPage, err error) {
 	const op = "flat.GetFlatListByHouseID"
 
 	log := s.log.With(
@@ -140,11 +359,14 @@ func (s *Service) GetFlatListByHouseID(ctx context.Context, houseID int64, userR
 		slog.Int64("house_id", houseID),
 	)
 
+	filter = normalizeFilter(filter)
+	filter.HouseIDs = []int64{houseID}
+
 	switch userRole {
 	case model.Moderator:
-		flatList, err = s.flatListForAdmin(ctx, houseID)
-	case model.Client:
-		flatList, err = s.flatListForClient(ctx, houseID)
+		page, err = s.flatListForAdmin(ctx, filter)
+	default:
+		page, err = s.flatListForClient(ctx, houseID, filter)
 	}
 
 	if err != nil {
@@ -152,59 +374,138 @@ func (s *Service) GetFlatListByHouseID(ctx context.Context, houseID int64, userR
 		return nil, err
 	}
 
-	if len(flatList) == 0 {
-		return []*model.Flat{}, nil
-	}
-
-	return flatList, nil
+	return page, nil
 }
 
-func (s *Service) flatListForClient(ctx context.Context, houseID int64) ([]*model.Flat, error) {
+func (s *Service) flatListForClient(ctx context.Context, houseID int64, filter model.FlatFilter) (*model.FlatPage, error) {
 	const op = "flat.flatListForClient"
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("house_id", houseID),
 	)
 
+	// Clients see only active flats
+	filter.Statuses = []model.FlatStatus{model.StatusApproved}
+	key := flatPageCacheKey(houseID, filter)
+
 	// Cache hit
-	cachedList, ok := s.cache.Get(houseID)
+	cachedPage, ok := s.cache.Get(key)
 	if ok {
-		var flats []*model.Flat
-		err := json.Unmarshal([]byte(cachedList), &flats)
+		var page model.FlatPage
+		err := json.Unmarshal([]byte(cachedPage), &page)
 		if err != nil {
 			log.Error("invalid json in cache", sl.Err(err))
-			s.cache.Remove(houseID)
+			s.cache.Remove(key)
 		} else {
-			return flats, nil
+			return &page, nil
 		}
 	}
 
 	// Fetch from database
-	flatList, err := s.flatRepository.FlatListByHouseID(ctx, houseID)
+	page, err := s.flatPageWithTotal(ctx, filter)
 	if err != nil {
 		log.Error("failed to get flat list", sl.Err(err))
 		return nil, err
 	}
 
-	// Filter active flats
-	activeFlatList := make([]*model.Flat, 0, len(flatList))
-	for _, flat := range flatList {
-		if flat.Status == model.StatusApproved {
-			activeFlatList = append(activeFlatList, flat)
-		}
-	}
-
 	// Cache result
-	flatsJSON, err := json.Marshal(activeFlatList)
+	pageJSON, err := json.Marshal(page)
 	if err != nil {
 		log.Error("failed to marshal flat list", sl.Err(err))
 	} else {
-		s.cache.Set(houseID, string(flatsJSON))
+		s.cache.Set(key, string(pageJSON))
 	}
 
-	return activeFlatList, nil
+	return page, nil
 }
 
-func (s *Service) flatListForAdmin(ctx context.Context, houseID int64) ([]*model.Flat, error) {
-	return s.flatRepository.FlatListByHouseID(ctx, houseID)
+func (s *Service) flatListForAdmin(ctx context.Context, filter model.FlatFilter) (*model.FlatPage, error) {
+	return s.flatPageWithTotal(ctx, filter)
+}
+
+const (
+	defaultPageSize = int64(20)
+	maxPageSize     = int64(100)
+)
+
+// SearchFlats retrieves a page of flats across all houses matching the filter.
+// Clients see only approved flats while moderators see flats in every status.
+func (s *Service) SearchFlats(ctx context.Context, filter model.FlatFilter, userRole model.UserType) (*model.FlatPage, error) {
+	const op = "flat.SearchFlats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_type", string(userRole)),
+	)
+
+	filter = normalizeFilter(filter)
+	if userRole != model.Moderator {
+		filter.Statuses = []model.FlatStatus{model.StatusApproved}
+	}
+
+	page, err := s.flatPage(ctx, filter)
+	if err != nil {
+		log.Error("failed to search flats", sl.Err(err))
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// normalizeFilter applies the default sort order and page size to the filter.
+func normalizeFilter(filter model.FlatFilter) model.FlatFilter {
+	if filter.Sort == "" {
+		filter.Sort = model.SortIDAsc
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		filter.Limit = defaultPageSize
+	}
+	return filter
+}
+
+// flatPage retrieves a single page of flats matching the normalized filter.
+func (s *Service) flatPage(ctx context.Context, filter model.FlatFilter) (*model.FlatPage, error) {
+	limit := filter.Limit
+
+	// Fetch one extra flat to find out whether the next page exists
+	filter.Limit++
+	flatList, err := s.flatRepository.SearchFlats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.FlatPage{Flats: flatList}
+	if int64(len(flatList)) > limit {
+		page.Flats = flatList[:limit]
+		page.NextCursor = filter.Sort.CursorOf(page.Flats[limit-1]).Encode()
+	}
+
+	err = s.fillPhotos(ctx, page.Flats)
+	if err != nil {
+		return nil, err
+	}
+	for _, flat := range page.Flats {
+		flat.FillPriceChange()
+	}
+	if page.Flats == nil {
+		page.Flats = []*model.Flat{}
+	}
+
+	return page, nil
+}
+
+// flatPageWithTotal retrieves a page of flats along with the total number
+// of flats matching the filter.
+func (s *Service) flatPageWithTotal(ctx context.Context, filter model.FlatFilter) (*model.FlatPage, error) {
+	page, err := s.flatPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Total, err = s.flatRepository.CountFlats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
@@ -6,24 +6,48 @@ import (
 	"avito-backend-bootcamp/internal/model"
 	mock "avito-backend-bootcamp/internal/service/flat/mocks"
 	"avito-backend-bootcamp/pkg/utils/sl"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"image"
+	"image/png"
 	"testing"
+	"time"
 
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var (
+	testModeratorID = uuid.MustParse("6f1c3a52-8d1e-4f0e-9a57-0b5b6a8e2c11")
+	testModerator   = model.Actor{ID: testModeratorID, Role: model.Moderator}
+	testOwnerID     = uuid.MustParse("0b8f7a3e-2c6d-4f1a-b5e9-7d3c2a1f9e40")
+	testOwner       = model.Actor{ID: testOwnerID, Role: model.Client}
+)
+
 const (
 	testHouseID = int64(123)
+	testNumber  = int64(7)
 	testPrice   = int64(100000)
 	testRooms   = int64(3)
 	testID      = int64(1)
 	testStatus  = model.StatusCreated
 )
 
+func newTestNewFlat() model.NewFlat {
+	return model.NewFlat{
+		HouseID: testHouseID,
+		Number:  testNumber,
+		Price:   testPrice,
+		Rooms:   testRooms,
+		OwnerID: testOwnerID,
+	}
+}
+
 func newTestFlat() *model.Flat {
 	return &model.Flat{
 		ID:      testID,
@@ -39,9 +63,14 @@ func TestService_CreateFlat(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		mockRepo := mock.NewMockFlatRepository(ctrl)
-		mockRepo.EXPECT().
-			SaveFlat(gomock.Any(), testHouseID, testPrice, testRooms).
+		m := newMock(ctrl)
+		m.trManager.EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.EXPECT().
+			SaveFlat(gomock.Any(), newTestNewFlat()).
 			Return(&model.Flat{
 				HouseID: testHouseID,
 				ID:      testID,
@@ -49,53 +78,125 @@ func TestService_CreateFlat(t *testing.T) {
 				Rooms:   testRooms,
 				Status:  testStatus,
 			}, nil)
+		m.historyRepository.EXPECT().
+			SaveFlatPriceChange(gomock.Any(), testID, testPrice).
+			Return(nil)
+		m.expectPass()
 
 		s := &Service{
-			flatRepository: mockRepo,
-			log:            sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+			log:               sl.SetupLogger(),
 		}
 
-		flat, err := s.CreateFlat(context.Background(), testHouseID, testPrice, testRooms)
+		flat, err := s.CreateFlat(context.Background(), newTestNewFlat())
 
 		require.NoError(t, err)
 		assert.Equal(t, flat, newTestFlat())
 	})
 
-	t.Run("house not exist", func(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{
+			name:    "house not exist",
+			repoErr: repoErr.ErrConstraintViolation,
+			wantErr: ErrHouseNotExist,
+		},
+		{
+			name:    "flat number taken",
+			repoErr: repoErr.ErrAlreadyExists,
+			wantErr: ErrFlatNumberTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newMock(ctrl)
+			m.trManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+			m.flatRepository.EXPECT().
+				SaveFlat(gomock.Any(), newTestNewFlat()).
+				Return(nil, tt.repoErr)
+
+			s := &Service{
+				flatRepository:    m.flatRepository,
+				historyRepository: m.historyRepository,
+				trManager:         m.trManager,
+				log:               sl.SetupLogger(),
+			}
+
+			_, err := s.CreateFlat(context.Background(), newTestNewFlat())
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	t.Run("kitchen larger than flat", func(t *testing.T) {
+		area, kitchen := 30.0, 35.0
+		newFlat := newTestNewFlat()
+		newFlat.TotalArea = &area
+		newFlat.KitchenArea = &kitchen
+
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		mockRepo := mock.NewMockFlatRepository(ctrl)
-		mockRepo.EXPECT().
-			SaveFlat(gomock.Any(), testHouseID, testPrice, testRooms).
-			Return(nil, repoErr.ErrConstraintViolation)
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
 
 		s := &Service{
-			flatRepository: mockRepo,
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
 			log:            sl.SetupLogger(),
 		}
 
-		_, err := s.CreateFlat(context.Background(), testHouseID, testPrice, testRooms)
+		_, err := s.CreateFlat(context.Background(), newFlat)
 
-		require.Error(t, err)
-		assert.Equal(t, ErrHouseNotExist, err)
+		assert.Equal(t, model.ErrKitchenAreaTooLarge, err)
 	})
 
-	t.Run("error saving flat", func(t *testing.T) {
+	t.Run("error saving price history", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		mockRepo := mock.NewMockFlatRepository(ctrl)
-		mockRepo.EXPECT().
-			SaveFlat(gomock.Any(), testHouseID, testPrice, testRooms).
-			Return(nil, errors.New("failed to save flat"))
+		m := newMock(ctrl)
+		m.trManager.EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.EXPECT().
+			SaveFlat(gomock.Any(), newTestNewFlat()).
+			Return(newTestFlat(), nil)
+		m.historyRepository.EXPECT().
+			SaveFlatPriceChange(gomock.Any(), testID, testPrice).
+			Return(errors.New("failed to save price"))
 
 		s := &Service{
-			flatRepository: mockRepo,
-			log:            sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			trManager:         m.trManager,
+			log:               sl.SetupLogger(),
 		}
 
-		_, err := s.CreateFlat(context.Background(), testHouseID, testPrice, testRooms)
+		_, err := s.CreateFlat(context.Background(), newTestNewFlat())
 
 		require.Error(t, err)
 		assert.NotEqual(t, ErrHouseNotExist, err)
@@ -103,21 +204,54 @@ func TestService_CreateFlat(t *testing.T) {
 }
 
 type mocks struct {
-	flatRepository  *mock.MockFlatRepository
-	eventRepository *mock.MockEventRepository
-	cache           *mock.MockCache
-	trManager       *mock.MockTrManager
+	flatRepository    *mock.MockFlatRepository
+	houseRepository   *mock.MockHouseRepository
+	historyRepository *mock.MockFlatHistoryRepository
+	eventRepository   *mock.MockEventRepository
+	photoRepository   *mock.MockFlatPhotoRepository
+	storage           *mock.MockBlobStorage
+	premoderation     *mock.MockPremoderation
+	cache             *mock.MockCache
+	trManager         *mock.MockTrManager
 }
 
 func newMock(ctrl *gomock.Controller) mocks {
 	return mocks{
-		flatRepository:  mock.NewMockFlatRepository(ctrl),
-		eventRepository: mock.NewMockEventRepository(ctrl),
-		cache:           mock.NewMockCache(ctrl),
-		trManager:       mock.NewMockTrManager(ctrl),
+		flatRepository:    mock.NewMockFlatRepository(ctrl),
+		houseRepository:   mock.NewMockHouseRepository(ctrl),
+		historyRepository: mock.NewMockFlatHistoryRepository(ctrl),
+		eventRepository:   mock.NewMockEventRepository(ctrl),
+		photoRepository:   mock.NewMockFlatPhotoRepository(ctrl),
+		storage:           mock.NewMockBlobStorage(ctrl),
+		premoderation:     mock.NewMockPremoderation(ctrl),
+		cache:             mock.NewMockCache(ctrl),
+		trManager:         mock.NewMockTrManager(ctrl),
 	}
 }
 
+// expectNoPhotos lets the service find no photos of the flats it returns.
+func (m mocks) expectNoPhotos() {
+	m.photoRepository.
+		EXPECT().
+		FlatPhotos(gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+}
+
+// expectPass lets the flat pass the premoderation rules.
+func (m mocks) expectPass() {
+	m.premoderation.
+		EXPECT().
+		Evaluate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, flat *model.Flat) ([]*model.RuleDecision, error) {
+			return []*model.RuleDecision{{FlatID: flat.ID, Action: model.RuleActionPass}}, nil
+		})
+	m.historyRepository.
+		EXPECT().
+		SaveFlatRuleDecisions(gomock.Any(), gomock.Any()).
+		Return(nil)
+}
+
 func TestUpdateFlat(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -148,12 +282,120 @@ func TestUpdateFlat(t *testing.T) {
 			trManager:       m.trManager,
 		}
 
-		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.StatusApproved)
+		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
 
 		assert.NoError(t, err)
 		assert.Equal(t, resultFlat.Status, model.StatusApproved)
 	})
 
+	t.Run("approve records history and publishes event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusOnModeration,
+				NewStatus: model.StatusApproved,
+				ActorID:   testModeratorID,
+				ActorRole: model.Moderator,
+			}).
+			Return(nil)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+		m.flatRepository.
+			EXPECT().
+			MarkHouseFlatPublished(gomock.Any(), int64(10)).
+			Return(nil)
+		m.eventRepository.
+			EXPECT().
+			PublishEvent(gomock.Any(), model.FlatApproved, `{"house_id": 10, "flat_id": 1}`).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusApproved, resultFlat.Status)
+		assert.Nil(t, resultFlat.ModeratorID)
+	})
+
+	t.Run("failed to mark house flat published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), gomock.Any()).
+			Return(nil)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+		m.flatRepository.
+			EXPECT().
+			MarkHouseFlatPublished(gomock.Any(), int64(10)).
+			Return(errors.New("failed to update house"))
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
+
+		assert.Error(t, err)
+	})
+
 	t.Run("flat not found", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -170,7 +412,7 @@ func TestUpdateFlat(t *testing.T) {
 			flatRepository: m.flatRepository,
 		}
 
-		_, err := service.UpdateFlat(context.Background(), 1, model.StatusApproved)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
 
 		assert.Equal(t, err, ErrFlatNotExist)
 	})
@@ -191,7 +433,7 @@ func TestUpdateFlat(t *testing.T) {
 			flatRepository: m.flatRepository,
 		}
 
-		_, err := service.UpdateFlat(context.Background(), 1, model.StatusApproved)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
 
 		assert.Error(t, err)
 		assert.NotEqual(t, err, ErrFlatNotExist)
@@ -218,202 +460,2614 @@ func TestUpdateFlat(t *testing.T) {
 			trManager:      m.trManager,
 		}
 
-		_, err := service.UpdateFlat(context.Background(), 1, model.StatusApproved)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
 
 		assert.Error(t, err)
 	})
 
-	t.Run("invalid status transition", func(t *testing.T) {
+	t.Run("flat moderated by another moderator", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
+		anotherModeratorID := uuid.New()
 		m.flatRepository.
 			EXPECT().
 			GetFlat(gomock.Any(), int64(1)).
-			Return(&model.Flat{Status: model.StatusOnModeration}, nil)
+			Return(&model.Flat{Status: model.StatusOnModeration, ModeratorID: &anotherModeratorID}, nil)
 
 		service := &Service{
 			log:            sl.SetupLogger(),
 			flatRepository: m.flatRepository,
 		}
 
-		_, err := service.UpdateFlat(context.Background(), 1, model.StatusOnModeration)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
 
-		assert.Equal(t, err, model.ErrImpossibleTransition)
+		assert.Equal(t, err, model.ErrForeignModeration)
 	})
-}
 
-func TestFlatListForClient(t *testing.T) {
-	t.Run("cache hit", func(t *testing.T) {
+	t.Run("decline with reason", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
-		houseID := int64(10)
-		flatList := []*model.Flat{
-			{ID: 1, HouseID: houseID, Status: model.StatusApproved},
-			{ID: 2, HouseID: houseID, Status: model.StatusApproved},
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
 		}
-		flatsJSON, _ := json.Marshal(flatList)
 
-		m.cache.
+		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{
+			Status:  model.StatusDeclined,
+			Reason:  model.ReasonInvalidPrice,
+			Comment: "price is too low",
+		}, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeclined, resultFlat.Status)
+		assert.Equal(t, model.ReasonInvalidPrice, *resultFlat.DeclineReason)
+		assert.Equal(t, "price is too low", *resultFlat.DeclineComment)
+	})
+
+	t.Run("decline without reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
 			EXPECT().
-			Get(houseID).
-			Return(string(flatsJSON), true)
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
 
 		service := &Service{
 			log:            sl.SetupLogger(),
 			flatRepository: m.flatRepository,
-			cache:          m.cache,
 		}
 
-		resultFlatList, err := service.flatListForClient(context.Background(), houseID)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusDeclined}, testModerator)
 
-		assert.NoError(t, err)
-		assert.Equal(t, resultFlatList, flatList)
+		assert.Equal(t, model.ErrDeclineReasonMissing, err)
 	})
 
-	t.Run("cache hit with invalid json", func(t *testing.T) {
+	t.Run("invalid status transition", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
-		houseID := int64(10)
-		invalidJSON := []byte("invalid json")
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{Status: model.StatusOnModeration}, nil)
 
-		m.cache.
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusOnModeration}, testModerator)
+
+		assert.Equal(t, err, model.ErrImpossibleTransition)
+	})
+
+	t.Run("approved flat is moderated again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
 			EXPECT().
-			Get(houseID).
-			Return(string(invalidJSON), true)
-		m.cache.
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved}, nil)
+		m.trManager.
 			EXPECT().
-			Remove(houseID)
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
 		m.flatRepository.
 			EXPECT().
-			FlatListByHouseID(gomock.Any(), gomock.Any()).
-			Return(nil, nil)
+			UpdateFlat(gomock.Any(), &model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), gomock.Any()).
+			Return(nil)
 		m.cache.
 			EXPECT().
-			Set(houseID, gomock.Any())
+			RemoveFunc(gomock.Any())
 
 		service := &Service{
-			log:            sl.SetupLogger(),
-			flatRepository: m.flatRepository,
-			cache:          m.cache,
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
 		}
 
-		resultFlatList, err := service.flatListForClient(context.Background(), houseID)
+		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusOnModeration}, testModerator)
 
-		assert.NoError(t, err)
-		assert.Equal(t, resultFlatList, []*model.Flat{})
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusOnModeration, resultFlat.Status)
 	})
 
-	t.Run("cache miss", func(t *testing.T) {
+	t.Run("owner resubmits declined flat", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
-		houseID := int64(10)
-		flatList := []*model.Flat{
-			{ID: 1, HouseID: houseID, Status: model.StatusApproved},
-			{ID: 2, HouseID: houseID, Status: model.StatusApproved},
-		}
-
-		m.cache.
+		reason := model.ReasonInvalidPrice
+		m.flatRepository.
 			EXPECT().
-			Get(houseID).
-			Return("", false)
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusDeclined, OwnerID: &testOwnerID, ModeratorID: &testModeratorID, DeclineReason: &reason}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
 		m.flatRepository.
 			EXPECT().
-			FlatListByHouseID(gomock.Any(), houseID).
-			Return(flatList, nil)
-		m.cache.
+			UpdateFlat(gomock.Any(), &model.Flat{ID: 1, HouseID: 10, Status: model.StatusCreated, OwnerID: &testOwnerID}).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
 			EXPECT().
-			Set(houseID, gomock.Any())
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusDeclined,
+				NewStatus: model.StatusCreated,
+				ActorID:   testOwnerID,
+				ActorRole: model.Client,
+			}).
+			Return(nil)
+
 		service := &Service{
-			log:            sl.SetupLogger(),
-			flatRepository: m.flatRepository,
-			cache:          m.cache,
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			trManager:         m.trManager,
 		}
 
-		resultFlatList, err := service.flatListForClient(context.Background(), houseID)
+		resultFlat, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusCreated}, testOwner)
 
-		assert.NoError(t, err)
-		assert.Equal(t, resultFlatList, flatList)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusCreated, resultFlat.Status)
+		assert.Nil(t, resultFlat.DeclineReason)
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("client can not approve", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
-		houseID := int64(10)
-		databaseError := errors.New("database error")
-
-		m.cache.
-			EXPECT().
-			Get(houseID).
-			Return("", false)
 		m.flatRepository.
 			EXPECT().
-			FlatListByHouseID(gomock.Any(), houseID).
-			Return(nil, databaseError)
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusOnModeration, OwnerID: &testOwnerID}, nil)
 
 		service := &Service{
 			log:            sl.SetupLogger(),
 			flatRepository: m.flatRepository,
-			cache:          m.cache,
 		}
 
-		resultFlatList, err := service.flatListForClient(context.Background(), houseID)
+		_, err := service.UpdateFlat(context.Background(), 1, model.FlatStatusUpdate{Status: model.StatusApproved}, testOwner)
 
-		assert.Error(t, err)
-		assert.Equal(t, err, databaseError)
-		assert.Nil(t, resultFlatList)
+		assert.Equal(t, model.ErrTransitionForbidden, err)
 	})
+}
 
-	t.Run("filter active flats", func(t *testing.T) {
+func TestGetFlatTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		flat   *model.Flat
+		actor  model.Actor
+		expect []model.FlatStatus
+	}{
+		{
+			name:   "moderator of created flat",
+			flat:   &model.Flat{ID: 1, Status: model.StatusCreated},
+			actor:  testModerator,
+			expect: []model.FlatStatus{model.StatusOnModeration},
+		},
+		{
+			name:   "moderator of own moderation",
+			flat:   &model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID},
+			actor:  testModerator,
+			expect: []model.FlatStatus{model.StatusApproved, model.StatusDeclined, model.StatusCreated},
+		},
+		{
+			name:   "moderator of foreign moderation",
+			flat:   &model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testOwnerID},
+			actor:  testModerator,
+			expect: []model.FlatStatus{},
+		},
+		{
+			name:   "owner of declined flat",
+			flat:   &model.Flat{ID: 1, Status: model.StatusDeclined, OwnerID: &testOwnerID},
+			actor:  testOwner,
+			expect: []model.FlatStatus{model.StatusCreated, model.StatusArchived, model.StatusSold},
+		},
+		{
+			name:   "client of foreign approved flat",
+			flat:   &model.Flat{ID: 1, Status: model.StatusApproved},
+			actor:  testOwner,
+			expect: []model.FlatStatus{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newMock(ctrl)
+			m.expectNoPhotos()
+
+			m.flatRepository.
+				EXPECT().
+				GetFlat(gomock.Any(), int64(1)).
+				Return(tt.flat, nil)
+
+			service := &Service{
+				log:             sl.SetupLogger(),
+				flatRepository:  m.flatRepository,
+				photoRepository: m.photoRepository,
+			}
+
+			transitions, err := service.GetFlatTransitions(context.Background(), 1, tt.actor)
+
+			require.NoError(t, err)
+			statuses := []model.FlatStatus{}
+			for _, transition := range transitions {
+				assert.Equal(t, tt.flat.Status, transition.From)
+				statuses = append(statuses, transition.To)
+			}
+			assert.Equal(t, tt.expect, statuses)
+		})
+	}
+}
+
+func TestEditFlat(t *testing.T) {
+	newPrice := int64(200000)
+
+	t.Run("approved flat returns to moderation", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		m := newMock(ctrl)
 
-		houseID := int64(10)
-		flatList := []*model.Flat{
-			{ID: 1, HouseID: houseID, Status: model.StatusApproved},
-			{ID: 2, HouseID: houseID, Status: model.StatusOnModeration},
-			{ID: 3, HouseID: houseID, Status: model.StatusApproved},
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Price: 100000, Status: model.StatusApproved, OwnerID: &testOwnerID, ModeratorID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusApproved,
+				NewStatus: model.StatusCreated,
+				ActorID:   testOwnerID,
+				ActorRole: model.Client,
+			}).
+			Return(nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), int64(1), newPrice).
+			Return(nil)
+		m.expectPass()
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			cache:             m.cache,
+			trManager:         m.trManager,
 		}
 
-		m.cache.
+		flat, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, newPrice, flat.Price)
+		assert.Equal(t, model.StatusCreated, flat.Status)
+		assert.Nil(t, flat.ModeratorID)
+	})
+
+	t.Run("created flat keeps status", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
 			EXPECT().
-			Get(houseID).
-			Return("", false)
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Price: 100000, Status: model.StatusCreated, OwnerID: &testOwnerID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
 		m.flatRepository.
 			EXPECT().
-			FlatListByHouseID(gomock.Any(), houseID).
-			Return(flatList, nil)
-		m.cache.
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), int64(1), newPrice).
+			Return(nil)
+		m.expectPass()
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, newPrice, flat.Price)
+		assert.Equal(t, model.StatusCreated, flat.Status)
+		require.NotNil(t, flat.PreviousPrice)
+		assert.Equal(t, int64(100000), *flat.PreviousPrice)
+	})
+
+	t.Run("kitchen larger than existing area", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		area, kitchen := 30.0, 35.0
+		m.flatRepository.
 			EXPECT().
-			Set(houseID, gomock.Any())
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusCreated, OwnerID: &testOwnerID, FlatAttributes: model.FlatAttributes{TotalArea: &area}}, nil)
 
 		service := &Service{
 			log:            sl.SetupLogger(),
 			flatRepository: m.flatRepository,
-			cache:          m.cache,
 		}
 
-		resultFlatList, err := service.flatListForClient(context.Background(), houseID)
+		edit := model.FlatEdit{FlatAttributes: model.FlatAttributes{KitchenArea: &kitchen}}
+		_, err := service.EditFlat(context.Background(), 1, edit, testOwner)
 
-		assert.NoError(t, err)
-		assert.Equal(t, len(resultFlatList), 2)
-		assert.Equal(t, resultFlatList[0].ID, int64(1))
-		assert.Equal(t, resultFlatList[1].ID, int64(3))
+		assert.ErrorIs(t, err, model.ErrKitchenAreaTooLarge)
 	})
+
+	t.Run("not owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved, OwnerID: &testOwnerID}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testModerator)
+
+		assert.ErrorIs(t, err, ErrNotFlatOwner)
+	})
+
+	t.Run("moderator resubmits own declined flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Price: 100000, Status: model.StatusDeclined, OwnerID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusDeclined,
+				NewStatus: model.StatusCreated,
+				ActorID:   testModeratorID,
+				ActorRole: model.Moderator,
+			}).
+			Return(nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), int64(1), newPrice).
+			Return(nil)
+		m.expectPass()
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, newPrice, flat.Price)
+		assert.Equal(t, model.StatusCreated, flat.Status)
+	})
+
+	t.Run("flat on moderation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, OwnerID: &testOwnerID}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testOwner)
+
+		assert.ErrorIs(t, err, model.ErrFlatOnModeration)
+	})
+
+	t.Run("flat not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(nil, repository.ErrNotFound)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.EditFlat(context.Background(), 1, model.FlatEdit{Price: &newPrice}, testOwner)
+
+		assert.Equal(t, ErrFlatNotExist, err)
+	})
+}
+
+func TestFlatListForClient(t *testing.T) {
+	houseID := int64(10)
+	filter := model.FlatFilter{
+		HouseIDs: []int64{houseID},
+		Sort:     model.SortIDAsc,
+		Limit:    defaultPageSize,
+	}
+	clientFilter := filter
+	clientFilter.Statuses = []model.FlatStatus{model.StatusApproved}
+	cacheKey := "house:10:id_asc:20:"
+
+	t.Run("cache hit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		page := &model.FlatPage{
+			Flats: []*model.Flat{
+				{ID: 1, HouseID: houseID, Status: model.StatusApproved},
+				{ID: 2, HouseID: houseID, Status: model.StatusApproved},
+			},
+			Total: 2,
+		}
+		pageJSON, _ := json.Marshal(page)
+
+		m.cache.
+			EXPECT().
+			Get(cacheKey).
+			Return(string(pageJSON), true)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		resultPage, err := service.flatListForClient(context.Background(), houseID, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, resultPage, page)
+	})
+
+	t.Run("cache hit with invalid json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		invalidJSON := []byte("invalid json")
+
+		m.cache.
+			EXPECT().
+			Get(cacheKey).
+			Return(string(invalidJSON), true)
+		m.cache.
+			EXPECT().
+			Remove(cacheKey)
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		m.flatRepository.
+			EXPECT().
+			CountFlats(gomock.Any(), gomock.Any()).
+			Return(int64(0), nil)
+		m.cache.
+			EXPECT().
+			Set(cacheKey, gomock.Any())
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		resultPage, err := service.flatListForClient(context.Background(), houseID, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, resultPage.Flats, []*model.Flat{})
+	})
+
+	t.Run("cache miss", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		flatList := []*model.Flat{
+			{ID: 1, HouseID: houseID, Status: model.StatusApproved},
+			{ID: 2, HouseID: houseID, Status: model.StatusApproved},
+		}
+
+		pageFilter := clientFilter
+		pageFilter.Limit++
+
+		m.cache.
+			EXPECT().
+			Get(cacheKey).
+			Return("", false)
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), pageFilter).
+			Return(flatList, nil)
+		m.flatRepository.
+			EXPECT().
+			CountFlats(gomock.Any(), clientFilter).
+			Return(int64(2), nil)
+		m.cache.
+			EXPECT().
+			Set(cacheKey, gomock.Any())
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		resultPage, err := service.flatListForClient(context.Background(), houseID, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, resultPage, &model.FlatPage{Flats: flatList, Total: 2})
+	})
+
+	t.Run("pages are cached separately", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		cursor := model.Cursor{Value: 2, ID: 2}
+		pageFilter := filter
+		pageFilter.After = &cursor
+
+		m.cache.
+			EXPECT().
+			Get("house:10:id_asc:20:"+cursor.Encode()).
+			Return("", false)
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		m.flatRepository.
+			EXPECT().
+			CountFlats(gomock.Any(), gomock.Any()).
+			Return(int64(2), nil)
+		m.cache.
+			EXPECT().
+			Set("house:10:id_asc:20:"+cursor.Encode(), gomock.Any())
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		_, err := service.flatListForClient(context.Background(), houseID, pageFilter)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		databaseError := errors.New("database error")
+
+		m.cache.
+			EXPECT().
+			Get(cacheKey).
+			Return("", false)
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return(nil, databaseError)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		resultPage, err := service.flatListForClient(context.Background(), houseID, filter)
+
+		assert.Error(t, err)
+		assert.Equal(t, err, databaseError)
+		assert.Nil(t, resultPage)
+	})
+
+	t.Run("next page cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		pageFilter := filter
+		pageFilter.Limit = 2
+
+		m.cache.
+			EXPECT().
+			Get("house:10:id_asc:2:").
+			Return("", false)
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return([]*model.Flat{
+				{ID: 1, HouseID: houseID, Status: model.StatusApproved},
+				{ID: 3, HouseID: houseID, Status: model.StatusApproved},
+				{ID: 4, HouseID: houseID, Status: model.StatusApproved},
+			}, nil)
+		m.flatRepository.
+			EXPECT().
+			CountFlats(gomock.Any(), gomock.Any()).
+			Return(int64(3), nil)
+		m.cache.
+			EXPECT().
+			Set("house:10:id_asc:2:", gomock.Any())
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			cache:           m.cache,
+		}
+
+		resultPage, err := service.flatListForClient(context.Background(), houseID, pageFilter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(resultPage.Flats), 2)
+		assert.Equal(t, resultPage.Total, int64(3))
+		assert.Equal(t, resultPage.NextCursor, model.Cursor{Value: 3, ID: 3}.Encode())
+	})
+}
+
+func TestFlatPageCacheKey(t *testing.T) {
+	filter := model.FlatFilter{Sort: model.SortIDAsc, Limit: 20}
+	assert.Equal(t, "house:10:id_asc:20:", flatPageCacheKey(10, filter))
+
+	filter.AreaMin = 40.5
+	filter.FloorMax = 9
+	filter.Layouts = []model.FlatLayout{model.LayoutStudio, model.LayoutEuro2}
+	assert.Equal(t, "house:10:id_asc:20::area=40.5-0:floor=0-9:layout=studio,euro_2", flatPageCacheKey(10, filter))
+}
+
+func TestGetHouseStats(t *testing.T) {
+	rooms := []*model.RoomsPriceStats{
+		{Rooms: 1, Count: 3, MinPrice: 100, MaxPrice: 300, AvgPrice: 200, MedianPrice: 150},
+	}
+
+	t.Run("cache miss", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.cache.
+			EXPECT().
+			Get("house:10:stats").
+			Return("", false)
+		m.houseRepository.
+			EXPECT().
+			GetHouse(gomock.Any(), int64(10)).
+			Return(&model.House{ID: 10}, nil)
+		m.flatRepository.
+			EXPECT().
+			FlatPriceStats(gomock.Any(), int64(10)).
+			Return(rooms, nil)
+		m.cache.
+			EXPECT().
+			Set("house:10:stats", gomock.Any())
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			houseRepository: m.houseRepository,
+			cache:           m.cache,
+		}
+
+		stats, err := service.GetHouseStats(context.Background(), 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.HousePriceStats{HouseID: 10, Rooms: rooms}, stats)
+	})
+
+	t.Run("cache hit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		cached, err := json.Marshal(model.HousePriceStats{HouseID: 10, Rooms: rooms})
+		require.NoError(t, err)
+		m.cache.
+			EXPECT().
+			Get("house:10:stats").
+			Return(string(cached), true)
+
+		service := &Service{
+			log:   sl.SetupLogger(),
+			cache: m.cache,
+		}
+
+		stats, err := service.GetHouseStats(context.Background(), 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.HousePriceStats{HouseID: 10, Rooms: rooms}, stats)
+	})
+
+	t.Run("no approved flats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.cache.
+			EXPECT().
+			Get(gomock.Any()).
+			Return("", false)
+		m.houseRepository.
+			EXPECT().
+			GetHouse(gomock.Any(), int64(10)).
+			Return(&model.House{ID: 10}, nil)
+		m.flatRepository.
+			EXPECT().
+			FlatPriceStats(gomock.Any(), int64(10)).
+			Return(nil, nil)
+		m.cache.
+			EXPECT().
+			Set(gomock.Any(), `{"house_id":10,"rooms":[]}`)
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			houseRepository: m.houseRepository,
+			cache:           m.cache,
+		}
+
+		stats, err := service.GetHouseStats(context.Background(), 10)
+
+		require.NoError(t, err)
+		assert.Empty(t, stats.Rooms)
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.cache.
+			EXPECT().
+			Get("house:10:stats").
+			Return("", false)
+		m.houseRepository.
+			EXPECT().
+			GetHouse(gomock.Any(), int64(10)).
+			Return(nil, repository.ErrNotFound)
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			houseRepository: m.houseRepository,
+			cache:           m.cache,
+		}
+
+		_, err := service.GetHouseStats(context.Background(), 10)
+
+		assert.Equal(t, ErrHouseNotExist, err)
+	})
+
+	t.Run("invalidated with flat pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any()).
+			Do(func(fn func(key string) bool) {
+				assert.True(t, fn(houseStatsCacheKey(10)))
+				assert.False(t, fn(houseStatsCacheKey(1)))
+			})
+
+		service := &Service{cache: m.cache}
+
+		service.invalidateHouseCache(10)
+	})
+}
+
+func TestSearchFlats(t *testing.T) {
+	t.Run("client sees only approved flats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), model.FlatFilter{
+				Rooms:    []int64{2},
+				Statuses: []model.FlatStatus{model.StatusApproved},
+				Sort:     model.SortIDAsc,
+				Limit:    defaultPageSize + 1,
+			}).
+			Return([]*model.Flat{{ID: 1, Status: model.StatusApproved}}, nil)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		page, err := service.SearchFlats(context.Background(), model.FlatFilter{Rooms: []int64{2}}, model.Client)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Flat{{ID: 1, Status: model.StatusApproved}}, page.Flats)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("moderator sees every status", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), model.FlatFilter{
+				Sort:  model.SortPriceDesc,
+				Limit: 3,
+			}).
+			Return(nil, nil)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		page, err := service.SearchFlats(context.Background(), model.FlatFilter{Sort: model.SortPriceDesc, Limit: 2}, model.Moderator)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Flat{}, page.Flats)
+	})
+
+	t.Run("next page cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return([]*model.Flat{
+				{ID: 1, Price: 100},
+				{ID: 2, Price: 200},
+				{ID: 3, Price: 300},
+			}, nil)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		page, err := service.SearchFlats(context.Background(), model.FlatFilter{Sort: model.SortPriceAsc, Limit: 2}, model.Client)
+
+		require.NoError(t, err)
+		require.Len(t, page.Flats, 2)
+
+		cursor, err := model.ParseCursor(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, &model.Cursor{Value: 200, ID: 2}, cursor)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		databaseError := errors.New("database error")
+		m.flatRepository.
+			EXPECT().
+			SearchFlats(gomock.Any(), gomock.Any()).
+			Return(nil, databaseError)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		_, err := service.SearchFlats(context.Background(), model.FlatFilter{}, model.Client)
+
+		assert.Equal(t, databaseError, err)
+	})
+}
+
+func TestModerationQueue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		flatList := []*model.Flat{
+			{ID: 1, Status: model.StatusCreated},
+			{ID: 2, Status: model.StatusCreated},
+		}
+
+		m.flatRepository.
+			EXPECT().
+			FlatModerationQueue(gomock.Any(), int64(5)).
+			Return(flatList, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		result, err := service.ModerationQueue(context.Background(), 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, flatList, result)
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			FlatModerationQueue(gomock.Any(), defaultPageSize).
+			Return(nil, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		result, err := service.ModerationQueue(context.Background(), 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Flat{}, result)
+	})
+}
+
+func TestClaimFlat(t *testing.T) {
+	runInTx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			GetNextFlatForModeration(gomock.Any()).
+			Return(&model.Flat{ID: 1, Status: model.StatusCreated}, nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), &model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusCreated,
+				NewStatus: model.StatusOnModeration,
+				ActorID:   testModeratorID,
+				ActorRole: model.Moderator,
+			}).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.ClaimFlat(context.Background(), testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusOnModeration, flat.Status)
+		assert.Equal(t, &testModeratorID, flat.ModeratorID)
+	})
+
+	t.Run("empty queue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			GetNextFlatForModeration(gomock.Any()).
+			Return(nil, repository.ErrNotFound)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		_, err := service.ClaimFlat(context.Background(), testModerator)
+
+		assert.Equal(t, ErrModerationQueueEmpty, err)
+	})
+
+	t.Run("update flat error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		updateErr := errors.New("failed to update flat")
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			GetNextFlatForModeration(gomock.Any()).
+			Return(&model.Flat{ID: 1, Status: model.StatusCreated}, nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			Return(nil, updateErr)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		_, err := service.ClaimFlat(context.Background(), testModerator)
+
+		assert.Equal(t, updateErr, err)
+	})
+}
+
+func TestCreateFlatPremoderation(t *testing.T) {
+	rule, reason, details := "price_bounds", model.ReasonInvalidPrice, "price is too low"
+
+	t.Run("declined by rule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		decisions := []*model.RuleDecision{
+			{FlatID: testID, Rule: &rule, Action: model.RuleActionDecline, Reason: &reason, Details: &details},
+		}
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), newTestNewFlat()).
+			Return(newTestFlat(), nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), testID, testPrice).
+			Return(nil)
+		m.premoderation.
+			EXPECT().
+			Evaluate(gomock.Any(), newTestFlat()).
+			Return(decisions, nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatRuleDecisions(gomock.Any(), decisions).
+			Return(nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    testID,
+				OldStatus: model.StatusCreated,
+				NewStatus: model.StatusDeclined,
+				ActorID:   uuid.Nil,
+				ActorRole: model.System,
+			}).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.CreateFlat(context.Background(), newTestNewFlat())
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeclined, flat.Status)
+		require.NotNil(t, flat.DeclineReason)
+		assert.Equal(t, reason, *flat.DeclineReason)
+		require.NotNil(t, flat.DeclineComment)
+		assert.Equal(t, details, *flat.DeclineComment)
+		assert.Nil(t, flat.ModeratorID)
+	})
+
+	t.Run("flagged by rule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		decisions := []*model.RuleDecision{
+			{FlatID: testID, Rule: &rule, Action: model.RuleActionFlag, Reason: &reason, Details: &details},
+		}
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), newTestNewFlat()).
+			Return(newTestFlat(), nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), testID, testPrice).
+			Return(nil)
+		m.premoderation.
+			EXPECT().
+			Evaluate(gomock.Any(), newTestFlat()).
+			Return(decisions, nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatRuleDecisions(gomock.Any(), decisions).
+			Return(nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.CreateFlat(context.Background(), newTestNewFlat())
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusCreated, flat.Status)
+		assert.True(t, flat.Flagged)
+	})
+
+	t.Run("rules failure aborts creation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		rulesErr := errors.New("failed to find duplicates")
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), newTestNewFlat()).
+			Return(newTestFlat(), nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), testID, testPrice).
+			Return(nil)
+		m.premoderation.
+			EXPECT().
+			Evaluate(gomock.Any(), newTestFlat()).
+			Return(nil, rulesErr)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+		}
+
+		_, err := service.CreateFlat(context.Background(), newTestNewFlat())
+
+		assert.ErrorIs(t, err, rulesErr)
+	})
+}
+
+func TestGetFlatRuleDecisions(t *testing.T) {
+	t.Run("no decisions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1}, nil)
+		m.historyRepository.
+			EXPECT().
+			FlatRuleDecisions(gomock.Any(), int64(1), model.RuleDecisionFilter{Limit: defaultPageSize + 1}).
+			Return(nil, nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+		}
+
+		result, err := service.GetFlatRuleDecisions(context.Background(), 1, testModerator, model.RuleDecisionFilter{})
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.RuleDecisionPage{Decisions: []*model.RuleDecision{}}, result)
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		after := &model.Cursor{Value: 3, ID: 3}
+		decisions := []*model.RuleDecision{
+			{ID: 4, FlatID: 1, Action: model.RuleActionFlag},
+			{ID: 5, FlatID: 1, Action: model.RuleActionPass},
+			{ID: 6, FlatID: 1, Action: model.RuleActionPass},
+		}
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1}, nil)
+		m.historyRepository.
+			EXPECT().
+			FlatRuleDecisions(gomock.Any(), int64(1), model.RuleDecisionFilter{Limit: 3, After: after}).
+			Return(decisions, nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+		}
+
+		result, err := service.GetFlatRuleDecisions(context.Background(), 1, testModerator, model.RuleDecisionFilter{Limit: 2, After: after})
+
+		require.NoError(t, err)
+		assert.Equal(t, decisions[:2], result.Decisions)
+		assert.Equal(t, model.Cursor{Value: 5, ID: 5}.Encode(), result.NextCursor)
+	})
+
+	t.Run("flat not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(nil, repository.ErrNotFound)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.GetFlatRuleDecisions(context.Background(), 1, testModerator, model.RuleDecisionFilter{})
+
+		assert.Equal(t, ErrFlatNotExist, err)
+	})
+
+	t.Run("client forbidden", func(t *testing.T) {
+		service := &Service{log: sl.SetupLogger()}
+
+		_, err := service.GetFlatRuleDecisions(context.Background(), 1, testOwner, model.RuleDecisionFilter{})
+
+		assert.Equal(t, ErrHistoryForbidden, err)
+	})
+}
+
+func TestReleaseStaleModerations(t *testing.T) {
+	runInTx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		reason := "moderation timed out after 30m0s"
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			StaleModerationFlats(gomock.Any(), gomock.Any(), int64(releaseBatchSize)).
+			Return([]*model.Flat{{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}}, nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), &model.Flat{ID: 1, Status: model.StatusCreated}).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusOnModeration,
+				NewStatus: model.StatusCreated,
+				ActorID:   uuid.Nil,
+				ActorRole: model.System,
+				Reason:    &reason,
+			}).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			trManager:         m.trManager,
+		}
+
+		flats, err := service.ReleaseStaleModerations(context.Background(), 30*time.Minute)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Flat{{ID: 1, Status: model.StatusCreated}}, flats)
+	})
+
+	t.Run("nothing to release", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			StaleModerationFlats(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		flats, err := service.ReleaseStaleModerations(context.Background(), 30*time.Minute)
+
+		require.NoError(t, err)
+		assert.Empty(t, flats)
+	})
+
+	t.Run("update failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		updateErr := errors.New("connection lost")
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(runInTx)
+		m.flatRepository.
+			EXPECT().
+			StaleModerationFlats(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*model.Flat{{ID: 1, Status: model.StatusOnModeration}}, nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			Return(nil, updateErr)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		_, err := service.ReleaseStaleModerations(context.Background(), 30*time.Minute)
+
+		assert.Equal(t, updateErr, err)
+	})
+}
+
+func TestGetFlatHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		history := []*model.FlatStatusChange{
+			{ID: 1, FlatID: 1, OldStatus: model.StatusCreated, NewStatus: model.StatusOnModeration},
+			{ID: 2, FlatID: 1, OldStatus: model.StatusOnModeration, NewStatus: model.StatusApproved},
+		}
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1}, nil)
+		m.historyRepository.
+			EXPECT().
+			FlatStatusHistory(gomock.Any(), int64(1)).
+			Return(history, nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+		}
+
+		result, err := service.GetFlatHistory(context.Background(), 1, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, history, result)
+	})
+
+	t.Run("flat not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(nil, repository.ErrNotFound)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.GetFlatHistory(context.Background(), 1, testModerator)
+
+		assert.Equal(t, ErrFlatNotExist, err)
+	})
+
+	t.Run("client forbidden", func(t *testing.T) {
+		service := &Service{log: sl.SetupLogger()}
+
+		_, err := service.GetFlatHistory(context.Background(), 1, testOwner)
+
+		assert.Equal(t, ErrHistoryForbidden, err)
+	})
+}
+
+func TestGetFlatPriceHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		history := []*model.FlatPriceChange{
+			{ID: 1, FlatID: 1, Price: 100000},
+			{ID: 2, FlatID: 1, Price: 90000},
+		}
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusApproved}, nil)
+		m.historyRepository.
+			EXPECT().
+			FlatPriceHistory(gomock.Any(), int64(1)).
+			Return(history, nil)
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			photoRepository:   m.photoRepository,
+		}
+
+		result, err := service.GetFlatPriceHistory(context.Background(), 1, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, history, result)
+	})
+
+	t.Run("hidden flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusDeclined, OwnerID: &testModeratorID}, nil)
+
+		m.expectNoPhotos()
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		_, err := service.GetFlatPriceHistory(context.Background(), 1, testOwner)
+
+		assert.Equal(t, ErrFlatNotExist, err)
+	})
+}
+
+func TestGetFlatPriceChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newMock(ctrl)
+
+	previous := int64(120000)
+	m.flatRepository.
+		EXPECT().
+		GetFlat(gomock.Any(), int64(1)).
+		Return(&model.Flat{ID: 1, Price: 100000, PreviousPrice: &previous, Status: model.StatusApproved}, nil)
+
+	m.expectNoPhotos()
+
+	service := &Service{
+		log:             sl.SetupLogger(),
+		flatRepository:  m.flatRepository,
+		photoRepository: m.photoRepository,
+	}
+
+	flat, err := service.GetFlat(context.Background(), 1, testOwner)
+
+	require.NoError(t, err)
+	require.NotNil(t, flat.PriceChange)
+	assert.Equal(t, -16.67, *flat.PriceChange)
+}
+
+func TestGetFlat(t *testing.T) {
+	otherUser := model.Actor{ID: uuid.MustParse("9a4e1c7b-3d2f-4b8a-8e6c-5f1d2a7b9c30"), Role: model.Client}
+
+	cases := []struct {
+		name    string
+		status  model.FlatStatus
+		actor   model.Actor
+		visible bool
+	}{
+		{"approved flat is visible to anyone", model.StatusApproved, otherUser, true},
+		{"declined flat is visible to owner", model.StatusDeclined, testOwner, true},
+		{"created flat is visible to moderator", model.StatusCreated, testModerator, true},
+		{"declined flat is hidden from other users", model.StatusDeclined, otherUser, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newMock(ctrl)
+
+			m.flatRepository.
+				EXPECT().
+				GetFlat(gomock.Any(), int64(1)).
+				Return(&model.Flat{ID: 1, Status: tc.status, OwnerID: &testOwnerID}, nil)
+
+			m.expectNoPhotos()
+
+			service := &Service{
+				log:             sl.SetupLogger(),
+				flatRepository:  m.flatRepository,
+				photoRepository: m.photoRepository,
+			}
+
+			flat, err := service.GetFlat(context.Background(), 1, tc.actor)
+
+			if tc.visible {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), flat.ID)
+			} else {
+				assert.Equal(t, ErrFlatNotExist, err)
+			}
+		})
+	}
+}
+
+func TestOwnerFlats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newMock(ctrl)
+
+	filter := model.FlatFilter{
+		Statuses: []model.FlatStatus{model.StatusDeclined},
+		Sort:     model.SortIDAsc,
+		Limit:    defaultPageSize,
+		OwnerID:  testOwnerID,
+	}
+	m.flatRepository.
+		EXPECT().
+		SearchFlats(gomock.Any(), model.FlatFilter{
+			Statuses: filter.Statuses,
+			Sort:     filter.Sort,
+			Limit:    filter.Limit + 1,
+			OwnerID:  filter.OwnerID,
+		}).
+		Return([]*model.Flat{{ID: 1, Status: model.StatusDeclined, OwnerID: &testOwnerID}}, nil)
+	m.flatRepository.
+		EXPECT().
+		CountFlats(gomock.Any(), filter).
+		Return(int64(1), nil)
+
+	m.expectNoPhotos()
+
+	service := &Service{
+		log:             sl.SetupLogger(),
+		flatRepository:  m.flatRepository,
+		photoRepository: m.photoRepository,
+	}
+
+	page, err := service.OwnerFlats(context.Background(), testOwner, model.FlatFilter{
+		Statuses: []model.FlatStatus{model.StatusDeclined},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Flats, 1)
+	assert.Equal(t, int64(1), page.Total)
+}
+
+func TestCloseFlat(t *testing.T) {
+	t.Run("approved flat sold publishes event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved, OwnerID: &testOwnerID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusApproved,
+				NewStatus: model.StatusSold,
+				ActorID:   testOwnerID,
+				ActorRole: model.Client,
+			}).
+			Return(nil)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+		m.eventRepository.
+			EXPECT().
+			PublishEvent(gomock.Any(), model.FlatSold, `{"house_id": 10, "flat_id": 1}`).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.CloseFlat(context.Background(), 1, model.StatusSold, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusSold, flat.Status)
+	})
+
+	t.Run("created flat archived without event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusCreated, OwnerID: &testOwnerID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.CloseFlat(context.Background(), 1, model.StatusArchived, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusArchived, flat.Status)
+	})
+
+	t.Run("moderator archives own declined flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusDeclined, OwnerID: &testModeratorID}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusDeclined,
+				NewStatus: model.StatusArchived,
+				ActorID:   testModeratorID,
+				ActorRole: model.Moderator,
+			}).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		flat, err := service.CloseFlat(context.Background(), 1, model.StatusArchived, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusArchived, flat.Status)
+	})
+
+	t.Run("flat on moderation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, OwnerID: &testOwnerID}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.CloseFlat(context.Background(), 1, model.StatusArchived, testOwner)
+
+		assert.ErrorIs(t, err, model.ErrFlatOnModeration)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved, OwnerID: &testOwnerID}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.CloseFlat(context.Background(), 1, model.StatusSold, testModerator)
+
+		assert.ErrorIs(t, err, ErrNotFlatOwner)
+	})
+}
+
+func TestImportFlats(t *testing.T) {
+	t.Run("rows in unknown houses are reported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.trManager.
+			EXPECT().
+			DoWithSettings(gomock.Any(), nestedTx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Times(2)
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), model.NewFlat{HouseID: 1, Number: 1, Price: 1000, Rooms: 2, OwnerID: testOwnerID}).
+			Return(&model.Flat{ID: 10, HouseID: 1, Price: 1000}, nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), int64(10), int64(1000)).
+			Return(nil)
+		m.expectPass()
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), model.NewFlat{HouseID: 99, Number: 1, Price: 2000, Rooms: 3, OwnerID: testOwnerID}).
+			Return(nil, repoErr.ErrConstraintViolation)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+		}
+
+		results, err := service.ImportFlats(context.Background(), []model.NewFlat{
+			{HouseID: 1, Number: 1, Price: 1000, Rooms: 2},
+			{HouseID: 99, Number: 1, Price: 2000, Rooms: 3},
+		}, testOwnerID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []model.FlatImportResult{
+			{Flat: &model.Flat{ID: 10, HouseID: 1, Price: 1000}},
+			{Err: ErrHouseNotExist},
+		}, results)
+	})
+
+	t.Run("rows with inconsistent attributes are reported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		area, kitchen, largeKitchen := 50.0, 10.0, 60.0
+		layout := model.LayoutStudio
+		attributes := model.FlatAttributes{TotalArea: &area, KitchenArea: &kitchen, Layout: &layout}
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.trManager.
+			EXPECT().
+			DoWithSettings(gomock.Any(), nestedTx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Times(2)
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), model.NewFlat{HouseID: 1, Number: 1, Price: 1000, Rooms: 2, OwnerID: testOwnerID, FlatAttributes: attributes}).
+			Return(&model.Flat{ID: 10, HouseID: 1, Price: 1000, FlatAttributes: attributes}, nil)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatPriceChange(gomock.Any(), int64(10), int64(1000)).
+			Return(nil)
+		m.expectPass()
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			premoderation:     m.premoderation,
+			trManager:         m.trManager,
+		}
+
+		results, err := service.ImportFlats(context.Background(), []model.NewFlat{
+			{HouseID: 1, Number: 1, Price: 1000, Rooms: 2, FlatAttributes: attributes},
+			{HouseID: 1, Number: 2, Price: 2000, Rooms: 3, FlatAttributes: model.FlatAttributes{TotalArea: &area, KitchenArea: &largeKitchen}},
+		}, testOwnerID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []model.FlatImportResult{
+			{Flat: &model.Flat{ID: 10, HouseID: 1, Price: 1000, FlatAttributes: attributes}},
+			{Err: model.ErrKitchenAreaTooLarge},
+		}, results)
+	})
+
+	t.Run("unexpected error aborts import", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		dbErr := errors.New("connection lost")
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.trManager.
+			EXPECT().
+			DoWithSettings(gomock.Any(), nestedTx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			SaveFlat(gomock.Any(), model.NewFlat{HouseID: 1, Number: 1, Price: 1000, Rooms: 2, OwnerID: testOwnerID}).
+			Return(nil, dbErr)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		_, err := service.ImportFlats(context.Background(), []model.NewFlat{
+			{HouseID: 1, Number: 1, Price: 1000, Rooms: 2},
+			{HouseID: 2, Number: 1, Price: 2000, Rooms: 3},
+		}, testOwnerID)
+
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestModerateFlats(t *testing.T) {
+	t.Run("publishes one event per house", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(2)).
+			Return(&model.Flat{ID: 2, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(3)).
+			Return(&model.Flat{ID: 3, HouseID: 10, Status: model.StatusCreated}, nil)
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(4)).
+			Return(nil, repository.ErrNotFound)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			}).
+			Times(2)
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(2)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any()).
+			Times(2)
+		m.flatRepository.
+			EXPECT().
+			MarkHouseFlatPublished(gomock.Any(), int64(10)).
+			Return(nil).
+			Times(2)
+		m.eventRepository.
+			EXPECT().
+			PublishEvent(gomock.Any(), model.FlatApproved, `{"house_id":10,"flat_ids":[1,2]}`).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		results, err := service.ModerateFlats(context.Background(), []int64{1, 2, 3, 4}, model.FlatStatusUpdate{Status: model.StatusApproved}, testModerator)
+
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, model.StatusApproved, results[0].Flat.Status)
+		assert.Equal(t, model.StatusApproved, results[1].Flat.Status)
+		assert.Equal(t, model.FlatModerationResult{FlatID: 3, Err: model.ErrImpossibleTransition}, results[2])
+		assert.Equal(t, model.FlatModerationResult{FlatID: 4, Err: ErrFlatNotExist}, results[3])
+	})
+
+	t.Run("decline publishes no events", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusOnModeration, ModeratorID: &testModeratorID}, nil)
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			eventRepository:   m.eventRepository,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		update := model.FlatStatusUpdate{Status: model.StatusDeclined, Reason: model.ReasonDuplicate}
+		results, err := service.ModerateFlats(context.Background(), []int64{1}, update, testModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeclined, results[0].Flat.Status)
+	})
+}
+
+func TestUpdateFlatVersion(t *testing.T) {
+	t.Run("stale version is rejected before update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID, Version: 3}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		update := model.FlatStatusUpdate{Status: model.StatusApproved, Version: 2}
+		_, err := service.UpdateFlat(context.Background(), 1, update, testModerator)
+
+		assert.Equal(t, ErrFlatModified, err)
+	})
+
+	t.Run("concurrent update is rejected by repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusOnModeration, ModeratorID: &testModeratorID, Version: 3}, nil)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			Return(nil, repoErr.ErrVersionConflict)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+			trManager:      m.trManager,
+		}
+
+		update := model.FlatStatusUpdate{Status: model.StatusApproved, Version: 3}
+		_, err := service.UpdateFlat(context.Background(), 1, update, testModerator)
+
+		assert.ErrorIs(t, err, ErrFlatModified)
+	})
+}
+
+// newTestPhoto encodes a PNG image of the given size.
+func newTestPhoto(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestUploadFlatPhoto(t *testing.T) {
+	t.Run("approved flat returns to moderation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+		data := newTestPhoto(t, 640, 480)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved, OwnerID: &testOwnerID, ModeratorID: &testModeratorID}, nil)
+		m.storage.
+			EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(2)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.photoRepository.
+			EXPECT().
+			SaveFlatPhoto(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, photo *model.FlatPhoto) (*model.FlatPhoto, error) {
+				assert.Equal(t, "image/png", photo.ContentType)
+				assert.Equal(t, int64(len(data)), photo.Size)
+				saved := *photo
+				saved.ID = 5
+				return &saved, nil
+			})
+		m.flatRepository.
+			EXPECT().
+			UpdateFlat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, flat *model.Flat) (*model.Flat, error) {
+				assert.Equal(t, model.StatusCreated, flat.Status)
+				return flat, nil
+			})
+		m.historyRepository.
+			EXPECT().
+			SaveFlatStatusChange(gomock.Any(), &model.FlatStatusChange{
+				FlatID:    1,
+				OldStatus: model.StatusApproved,
+				NewStatus: model.StatusCreated,
+				ActorID:   testOwnerID,
+				ActorRole: model.Client,
+			}).
+			Return(nil)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+		m.storage.
+			EXPECT().
+			URL(gomock.Any()).
+			DoAndReturn(func(key string) string { return "/media/" + key }).
+			Times(2)
+
+		service := &Service{
+			log:               sl.SetupLogger(),
+			flatRepository:    m.flatRepository,
+			historyRepository: m.historyRepository,
+			photoRepository:   m.photoRepository,
+			storage:           m.storage,
+			cache:             m.cache,
+			trManager:         m.trManager,
+		}
+
+		photo, err := service.UploadFlatPhoto(context.Background(), 1, data, testOwner)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), photo.ID)
+		assert.Equal(t, "/media/"+photo.Key, photo.URL)
+		assert.Equal(t, "/media/"+photo.ThumbnailKey, photo.ThumbnailURL)
+	})
+
+	t.Run("files are removed when saving fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusCreated, OwnerID: &testOwnerID}, nil)
+		m.storage.
+			EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(2)
+		m.trManager.
+			EXPECT().
+			Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		m.photoRepository.
+			EXPECT().
+			SaveFlatPhoto(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("connection lost"))
+		m.storage.
+			EXPECT().
+			Delete(gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			storage:         m.storage,
+			trManager:       m.trManager,
+		}
+
+		_, err := service.UploadFlatPhoto(context.Background(), 1, newTestPhoto(t, 10, 10), testOwner)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		service := &Service{log: sl.SetupLogger()}
+
+		_, err := service.UploadFlatPhoto(context.Background(), 1, []byte("plain text"), testOwner)
+
+		assert.Equal(t, ErrUnsupportedPhoto, err)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		service := &Service{log: sl.SetupLogger()}
+
+		_, err := service.UploadFlatPhoto(context.Background(), 1, make([]byte, MaxPhotoSize+1), testOwner)
+
+		assert.Equal(t, ErrPhotoTooLarge, err)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusApproved, OwnerID: &testOwnerID}, nil)
+
+		service := &Service{
+			log:            sl.SetupLogger(),
+			flatRepository: m.flatRepository,
+		}
+
+		_, err := service.UploadFlatPhoto(context.Background(), 1, newTestPhoto(t, 10, 10), testModerator)
+
+		assert.Equal(t, ErrNotFlatOwner, err)
+	})
+}
+
+func TestDeleteFlatPhoto(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, HouseID: 10, Status: model.StatusApproved, OwnerID: &testOwnerID}, nil)
+		m.photoRepository.
+			EXPECT().
+			GetFlatPhoto(gomock.Any(), int64(5)).
+			Return(&model.FlatPhoto{ID: 5, FlatID: 1, Key: "a.png", ThumbnailKey: "a_thumb.jpg"}, nil)
+		m.photoRepository.
+			EXPECT().
+			DeleteFlatPhoto(gomock.Any(), int64(5)).
+			Return(nil)
+		m.storage.
+			EXPECT().
+			Delete(gomock.Any(), "a.png").
+			Return(nil)
+		m.storage.
+			EXPECT().
+			Delete(gomock.Any(), "a_thumb.jpg").
+			Return(nil)
+		m.cache.
+			EXPECT().
+			RemoveFunc(gomock.Any())
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+			storage:         m.storage,
+			cache:           m.cache,
+		}
+
+		err := service.DeleteFlatPhoto(context.Background(), 1, 5, testOwner)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("photo of another flat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := newMock(ctrl)
+
+		m.flatRepository.
+			EXPECT().
+			GetFlat(gomock.Any(), int64(1)).
+			Return(&model.Flat{ID: 1, Status: model.StatusCreated, OwnerID: &testOwnerID}, nil)
+		m.photoRepository.
+			EXPECT().
+			GetFlatPhoto(gomock.Any(), int64(5)).
+			Return(&model.FlatPhoto{ID: 5, FlatID: 2}, nil)
+
+		service := &Service{
+			log:             sl.SetupLogger(),
+			flatRepository:  m.flatRepository,
+			photoRepository: m.photoRepository,
+		}
+
+		err := service.DeleteFlatPhoto(context.Background(), 1, 5, testOwner)
+
+		assert.Equal(t, ErrFlatPhotoNotExist, err)
+	})
+}
+
+func TestGetFlatPhotos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newMock(ctrl)
+
+	m.flatRepository.
+		EXPECT().
+		GetFlat(gomock.Any(), int64(1)).
+		Return(&model.Flat{ID: 1, Status: model.StatusApproved}, nil)
+	m.photoRepository.
+		EXPECT().
+		FlatPhotos(gomock.Any(), []int64{1}).
+		Return([]*model.FlatPhoto{{ID: 5, FlatID: 1, Key: "a.png", ThumbnailKey: "a_thumb.jpg"}}, nil)
+	m.storage.
+		EXPECT().
+		URL(gomock.Any()).
+		DoAndReturn(func(key string) string { return "/media/" + key }).
+		Times(2)
+
+	service := &Service{
+		log:             sl.SetupLogger(),
+		flatRepository:  m.flatRepository,
+		photoRepository: m.photoRepository,
+		storage:         m.storage,
+	}
+
+	photos, err := service.GetFlatPhotos(context.Background(), 1, testOwner)
+
+	require.NoError(t, err)
+	require.Len(t, photos, 1)
+	assert.Equal(t, "/media/a.png", photos[0].URL)
+	assert.Equal(t, "/media/a_thumb.jpg", photos[0].ThumbnailURL)
 }
@@ -0,0 +1,74 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/infra/repository"
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+// houseStatsCacheKey builds the cache key for the price statistics of the house.
+// It shares the house prefix, so the statistics are invalidated with the flat pages.
+func houseStatsCacheKey(houseID int64) string {
+	return houseCachePrefix(houseID) + "stats"
+}
+
+// GetHouseStats retrieves price statistics of approved flats of the house
+// grouped by the number of rooms. Statistics are cached only for existing houses.
+func (s *Service) GetHouseStats(ctx context.Context, houseID int64) (*model.HousePriceStats, error) {
+	const op = "flat.GetHouseStats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("house_id", houseID),
+	)
+
+	key := houseStatsCacheKey(houseID)
+
+	// Cache hit
+	cachedStats, ok := s.cache.Get(key)
+	if ok {
+		var stats model.HousePriceStats
+		err := json.Unmarshal([]byte(cachedStats), &stats)
+		if err != nil {
+			log.Error("invalid json in cache", sl.Err(err))
+			s.cache.Remove(key)
+		} else {
+			return &stats, nil
+		}
+	}
+
+	// Fetch from database
+	_, err := s.houseRepository.GetHouse(ctx, houseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("house does not exist", sl.Err(err))
+			return nil, ErrHouseNotExist
+		}
+		log.Error("failed to find house", sl.Err(err))
+		return nil, err
+	}
+
+	rooms, err := s.flatRepository.FlatPriceStats(ctx, houseID)
+	if err != nil {
+		log.Error("failed to get flat price stats", sl.Err(err))
+		return nil, err
+	}
+	if rooms == nil {
+		rooms = []*model.RoomsPriceStats{}
+	}
+	stats := &model.HousePriceStats{HouseID: houseID, Rooms: rooms}
+
+	// Cache result
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		log.Error("failed to marshal flat price stats", sl.Err(err))
+	} else {
+		s.cache.Set(key, string(statsJSON))
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,55 @@
+package flat
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"avito-backend-bootcamp/pkg/utils/sl"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// GetFlatTransitions lists the status transitions of the flat
+// the actor is allowed to perform next.
+func (s *Service) GetFlatTransitions(ctx context.Context, flatID int64, actor model.Actor) ([]model.FlatTransition, error) {
+	const op = "flat.GetFlatTransitions"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("flat_id", flatID),
+		slog.String("actor_id", actor.ID.String()),
+	)
+
+	flat, err := s.GetFlat(ctx, flatID, actor)
+	if err != nil {
+		log.Error("failed to get flat", sl.Err(err))
+		return nil, err
+	}
+
+	return model.FlatTransitions.Available(flat, actor), nil
+}
+
+// applyTransitionEffects performs the side effects of the status transition
+// of the flat. It is expected to run in the transaction that updates the flat.
+func (s *Service) applyTransitionEffects(ctx context.Context, flat *model.Flat, transition model.FlatTransition) error {
+	if transition.InvalidateCache {
+		s.invalidateHouseCache(flat.HouseID)
+	}
+
+	if transition.MarkPublished {
+		err := s.flatRepository.MarkHouseFlatPublished(ctx, flat.HouseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if transition.Event == "" {
+		return nil
+	}
+
+	eventPayload := fmt.Sprintf(
+		`{"house_id": %d, "flat_id": %d}`,
+		flat.HouseID,
+		flat.ID,
+	)
+	return s.eventRepository.PublishEvent(ctx, transition.Event, eventPayload)
+}
@@ -0,0 +1,20 @@
+package house
+
+import (
+	"fmt"
+	"strings"
+)
+
+// houseCachePrefix returns the prefix shared by all cache keys of the house,
+// the same one the flat service uses for pages of flats and price statistics.
+func houseCachePrefix(houseID int64) string {
+	return fmt.Sprintf("house:%d:", houseID)
+}
+
+// invalidateHouseCache removes everything cached for the house.
+func (s *Service) invalidateHouseCache(houseID int64) {
+	prefix := houseCachePrefix(houseID)
+	s.cache.RemoveFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
@@ -3,8 +3,47 @@ package house
 import (
 	"avito-backend-bootcamp/internal/model"
 	"context"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 )
 
 type HouseRepository interface {
-	SaveHouse(ctx context.Context, address, developer string, year int64) (*model.House, error)
+	SaveHouse(ctx context.Context, newHouse model.NewHouse) (*model.House, error)
+	GetHouse(ctx context.Context, id int64) (*model.House, error)
+	GetHouseSummary(ctx context.Context, id int64) (*model.HouseSummary, error)
+	UpdateHouse(ctx context.Context, house *model.House) (*model.House, error)
+	LockHouseLocations(ctx context.Context) error
+	HousesWithin(ctx context.Context, lower, upper model.GeoPoint) ([]*model.House, error)
+	DeleteHouse(ctx context.Context, id int64) error
+	DeleteHouseFlats(ctx context.Context, houseID int64) (int64, error)
+	SearchHouses(ctx context.Context, filter model.HouseFilter) ([]*model.House, error)
+}
+
+type SubscriptionRepository interface {
+	DeleteHouseSubscriptions(ctx context.Context, houseID int64) (int64, error)
+}
+
+type FlatPhotoRepository interface {
+	HouseFlatPhotos(ctx context.Context, houseID int64) ([]*model.FlatPhoto, error)
+}
+
+type EventRepository interface {
+	DiscardHouseEvents(ctx context.Context, houseID int64) (int64, error)
+}
+
+type BlobStorage interface {
+	Delete(ctx context.Context, key string) error
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*model.GeoAddress, error)
+}
+
+type Cache interface {
+	RemoveFunc(fn func(key string) bool)
+}
+
+type TrManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) (err error)
+	DoWithSettings(ctx context.Context, s trm.Settings, fn func(ctx context.Context) error) (err error)
 }
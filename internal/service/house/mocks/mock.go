@@ -9,6 +9,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	trm "github.com/avito-tech/go-transaction-manager/trm/v2"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,17 +36,410 @@ func (m *MockHouseRepository) EXPECT() *MockHouseRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DeleteHouse mocks base method.
+func (m *MockHouseRepository) DeleteHouse(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouse", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouse indicates an expected call of DeleteHouse.
+func (mr *MockHouseRepositoryMockRecorder) DeleteHouse(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouse", reflect.TypeOf((*MockHouseRepository)(nil).DeleteHouse), ctx, id)
+}
+
+// DeleteHouseFlats mocks base method.
+func (m *MockHouseRepository) DeleteHouseFlats(ctx context.Context, houseID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseFlats", ctx, houseID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHouseFlats indicates an expected call of DeleteHouseFlats.
+func (mr *MockHouseRepositoryMockRecorder) DeleteHouseFlats(ctx, houseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseFlats", reflect.TypeOf((*MockHouseRepository)(nil).DeleteHouseFlats), ctx, houseID)
+}
+
+// GetHouse mocks base method.
+func (m *MockHouseRepository) GetHouse(ctx context.Context, id int64) (*model.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouse", ctx, id)
+	ret0, _ := ret[0].(*model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouse indicates an expected call of GetHouse.
+func (mr *MockHouseRepositoryMockRecorder) GetHouse(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouse", reflect.TypeOf((*MockHouseRepository)(nil).GetHouse), ctx, id)
+}
+
+// GetHouseSummary mocks base method.
+func (m *MockHouseRepository) GetHouseSummary(ctx context.Context, id int64) (*model.HouseSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseSummary", ctx, id)
+	ret0, _ := ret[0].(*model.HouseSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseSummary indicates an expected call of GetHouseSummary.
+func (mr *MockHouseRepositoryMockRecorder) GetHouseSummary(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseSummary", reflect.TypeOf((*MockHouseRepository)(nil).GetHouseSummary), ctx, id)
+}
+
+// HousesWithin mocks base method.
+func (m *MockHouseRepository) HousesWithin(ctx context.Context, lower, upper model.GeoPoint) ([]*model.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HousesWithin", ctx, lower, upper)
+	ret0, _ := ret[0].([]*model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HousesWithin indicates an expected call of HousesWithin.
+func (mr *MockHouseRepositoryMockRecorder) HousesWithin(ctx, lower, upper interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HousesWithin", reflect.TypeOf((*MockHouseRepository)(nil).HousesWithin), ctx, lower, upper)
+}
+
+// LockHouseLocations mocks base method.
+func (m *MockHouseRepository) LockHouseLocations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockHouseLocations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockHouseLocations indicates an expected call of LockHouseLocations.
+func (mr *MockHouseRepositoryMockRecorder) LockHouseLocations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockHouseLocations", reflect.TypeOf((*MockHouseRepository)(nil).LockHouseLocations), ctx)
+}
+
 // SaveHouse mocks base method.
-func (m *MockHouseRepository) SaveHouse(ctx context.Context, address, developer string, year int64) (*model.House, error) {
+func (m *MockHouseRepository) SaveHouse(ctx context.Context, newHouse model.NewHouse) (*model.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveHouse", ctx, address, developer, year)
+	ret := m.ctrl.Call(m, "SaveHouse", ctx, newHouse)
 	ret0, _ := ret[0].(*model.House)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveHouse indicates an expected call of SaveHouse.
-func (mr *MockHouseRepositoryMockRecorder) SaveHouse(ctx, address, developer, year interface{}) *gomock.Call {
+func (mr *MockHouseRepositoryMockRecorder) SaveHouse(ctx, newHouse interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHouse", reflect.TypeOf((*MockHouseRepository)(nil).SaveHouse), ctx, newHouse)
+}
+
+// SearchHouses mocks base method.
+func (m *MockHouseRepository) SearchHouses(ctx context.Context, filter model.HouseFilter) ([]*model.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchHouses", ctx, filter)
+	ret0, _ := ret[0].([]*model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchHouses indicates an expected call of SearchHouses.
+func (mr *MockHouseRepositoryMockRecorder) SearchHouses(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchHouses", reflect.TypeOf((*MockHouseRepository)(nil).SearchHouses), ctx, filter)
+}
+
+// UpdateHouse mocks base method.
+func (m *MockHouseRepository) UpdateHouse(ctx context.Context, house *model.House) (*model.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouse", ctx, house)
+	ret0, _ := ret[0].(*model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouse indicates an expected call of UpdateHouse.
+func (mr *MockHouseRepositoryMockRecorder) UpdateHouse(ctx, house interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouse", reflect.TypeOf((*MockHouseRepository)(nil).UpdateHouse), ctx, house)
+}
+
+// MockSubscriptionRepository is a mock of SubscriptionRepository interface.
+type MockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryMockRecorder
+}
+
+// MockSubscriptionRepositoryMockRecorder is the mock recorder for MockSubscriptionRepository.
+type MockSubscriptionRepositoryMockRecorder struct {
+	mock *MockSubscriptionRepository
+}
+
+// NewMockSubscriptionRepository creates a new mock instance.
+func NewMockSubscriptionRepository(ctrl *gomock.Controller) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteHouseSubscriptions mocks base method.
+func (m *MockSubscriptionRepository) DeleteHouseSubscriptions(ctx context.Context, houseID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseSubscriptions", ctx, houseID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHouseSubscriptions indicates an expected call of DeleteHouseSubscriptions.
+func (mr *MockSubscriptionRepositoryMockRecorder) DeleteHouseSubscriptions(ctx, houseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseSubscriptions", reflect.TypeOf((*MockSubscriptionRepository)(nil).DeleteHouseSubscriptions), ctx, houseID)
+}
+
+// MockFlatPhotoRepository is a mock of FlatPhotoRepository interface.
+type MockFlatPhotoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatPhotoRepositoryMockRecorder
+}
+
+// MockFlatPhotoRepositoryMockRecorder is the mock recorder for MockFlatPhotoRepository.
+type MockFlatPhotoRepositoryMockRecorder struct {
+	mock *MockFlatPhotoRepository
+}
+
+// NewMockFlatPhotoRepository creates a new mock instance.
+func NewMockFlatPhotoRepository(ctrl *gomock.Controller) *MockFlatPhotoRepository {
+	mock := &MockFlatPhotoRepository{ctrl: ctrl}
+	mock.recorder = &MockFlatPhotoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatPhotoRepository) EXPECT() *MockFlatPhotoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// HouseFlatPhotos mocks base method.
+func (m *MockFlatPhotoRepository) HouseFlatPhotos(ctx context.Context, houseID int64) ([]*model.FlatPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HouseFlatPhotos", ctx, houseID)
+	ret0, _ := ret[0].([]*model.FlatPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HouseFlatPhotos indicates an expected call of HouseFlatPhotos.
+func (mr *MockFlatPhotoRepositoryMockRecorder) HouseFlatPhotos(ctx, houseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HouseFlatPhotos", reflect.TypeOf((*MockFlatPhotoRepository)(nil).HouseFlatPhotos), ctx, houseID)
+}
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DiscardHouseEvents mocks base method.
+func (m *MockEventRepository) DiscardHouseEvents(ctx context.Context, houseID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscardHouseEvents", ctx, houseID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscardHouseEvents indicates an expected call of DiscardHouseEvents.
+func (mr *MockEventRepositoryMockRecorder) DiscardHouseEvents(ctx, houseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardHouseEvents", reflect.TypeOf((*MockEventRepository)(nil).DiscardHouseEvents), ctx, houseID)
+}
+
+// MockBlobStorage is a mock of BlobStorage interface.
+type MockBlobStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStorageMockRecorder
+}
+
+// MockBlobStorageMockRecorder is the mock recorder for MockBlobStorage.
+type MockBlobStorageMockRecorder struct {
+	mock *MockBlobStorage
+}
+
+// NewMockBlobStorage creates a new mock instance.
+func NewMockBlobStorage(ctrl *gomock.Controller) *MockBlobStorage {
+	mock := &MockBlobStorage{ctrl: ctrl}
+	mock.recorder = &MockBlobStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStorage) EXPECT() *MockBlobStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStorage)(nil).Delete), ctx, key)
+}
+
+// MockGeocoder is a mock of Geocoder interface.
+type MockGeocoder struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeocoderMockRecorder
+}
+
+// MockGeocoderMockRecorder is the mock recorder for MockGeocoder.
+type MockGeocoderMockRecorder struct {
+	mock *MockGeocoder
+}
+
+// NewMockGeocoder creates a new mock instance.
+func NewMockGeocoder(ctrl *gomock.Controller) *MockGeocoder {
+	mock := &MockGeocoder{ctrl: ctrl}
+	mock.recorder = &MockGeocoderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeocoder) EXPECT() *MockGeocoderMockRecorder {
+	return m.recorder
+}
+
+// Geocode mocks base method.
+func (m *MockGeocoder) Geocode(ctx context.Context, address string) (*model.GeoAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Geocode", ctx, address)
+	ret0, _ := ret[0].(*model.GeoAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Geocode indicates an expected call of Geocode.
+func (mr *MockGeocoderMockRecorder) Geocode(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Geocode", reflect.TypeOf((*MockGeocoder)(nil).Geocode), ctx, address)
+}
+
+// MockCache is a mock of Cache interface.
+type MockCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMockRecorder
+}
+
+// MockCacheMockRecorder is the mock recorder for MockCache.
+type MockCacheMockRecorder struct {
+	mock *MockCache
+}
+
+// NewMockCache creates a new mock instance.
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	mock := &MockCache{ctrl: ctrl}
+	mock.recorder = &MockCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCache) EXPECT() *MockCacheMockRecorder {
+	return m.recorder
+}
+
+// RemoveFunc mocks base method.
+func (m *MockCache) RemoveFunc(fn func(string) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveFunc", fn)
+}
+
+// RemoveFunc indicates an expected call of RemoveFunc.
+func (mr *MockCacheMockRecorder) RemoveFunc(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFunc", reflect.TypeOf((*MockCache)(nil).RemoveFunc), fn)
+}
+
+// MockTrManager is a mock of TrManager interface.
+type MockTrManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrManagerMockRecorder
+}
+
+// MockTrManagerMockRecorder is the mock recorder for MockTrManager.
+type MockTrManagerMockRecorder struct {
+	mock *MockTrManager
+}
+
+// NewMockTrManager creates a new mock instance.
+func NewMockTrManager(ctrl *gomock.Controller) *MockTrManager {
+	mock := &MockTrManager{ctrl: ctrl}
+	mock.recorder = &MockTrManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrManager) EXPECT() *MockTrManagerMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockTrManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockTrManagerMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTrManager)(nil).Do), ctx, fn)
+}
+
+// DoWithSettings mocks base method.
+func (m *MockTrManager) DoWithSettings(ctx context.Context, s trm.Settings, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoWithSettings", ctx, s, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DoWithSettings indicates an expected call of DoWithSettings.
+func (mr *MockTrManagerMockRecorder) DoWithSettings(ctx, s, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHouse", reflect.TypeOf((*MockHouseRepository)(nil).SaveHouse), ctx, address, developer, year)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoWithSettings", reflect.TypeOf((*MockTrManager)(nil).DoWithSettings), ctx, s, fn)
 }
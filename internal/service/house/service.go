@@ -6,23 +6,57 @@ import (
 	"avito-backend-bootcamp/pkg/utils/sl"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 )
 
 type Service struct {
-	log            *slog.Logger
-	houseRpository HouseRepository
+	log                    *slog.Logger
+	houseRpository         HouseRepository
+	subscriptionRepository SubscriptionRepository
+	photoRepository        FlatPhotoRepository
+	eventRepository        EventRepository
+	storage                BlobStorage
+	geocoder               Geocoder
+	cache                  Cache
+	trManager              TrManager
 }
 
-func New(log *slog.Logger, houseRpository HouseRepository) *Service {
+func New(
+	log *slog.Logger,
+	houseRpository HouseRepository,
+	subscriptionRepository SubscriptionRepository,
+	photoRepository FlatPhotoRepository,
+	eventRepository EventRepository,
+	storage BlobStorage,
+	geocoder Geocoder,
+	cache Cache,
+	trManager TrManager,
+) *Service {
 	return &Service{
-		log:            log,
-		houseRpository: houseRpository,
+		log:                    log,
+		houseRpository:         houseRpository,
+		subscriptionRepository: subscriptionRepository,
+		photoRepository:        photoRepository,
+		eventRepository:        eventRepository,
+		storage:                storage,
+		geocoder:               geocoder,
+		cache:                  cache,
+		trManager:              trManager,
 	}
 }
 
-var ErrAddressAlreadyUsed = errors.New("house with given address already exist")
+var (
+	ErrAddressAlreadyUsed = errors.New("house with given address already exist")
+	ErrHouseNotExist      = errors.New("house does not exist")
+	ErrHouseNearDuplicate = errors.New("house at nearly the same location already exist")
+)
+
+// Houses closer than nearDuplicateDistance meters to each other are considered the same house.
+const nearDuplicateDistance = 30.0
 
+// CreateHouse saves a new house at the geocoded address, rejecting
+// near-duplicates of the existing houses.
 func (s *Service) CreateHouse(ctx context.Context, address, developer string, year int64) (*model.House, error) {
 	const op = "house.CreateHouse"
 
@@ -33,15 +67,268 @@ func (s *Service) CreateHouse(ctx context.Context, address, developer string, ye
 		slog.Int64("year", year),
 	)
 
-	house, err := s.houseRpository.SaveHouse(ctx, address, developer, year)
+	location, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		log.Error("failed to geocode address", sl.Err(err))
+		return nil, err
+	}
+
+	// The near-duplicate check and the insert share the transaction
+	// to keep concurrent creations from passing the check together
+	var house *model.House
+	err = s.trManager.Do(ctx, func(ctx context.Context) (err error) {
+		err = s.checkNearDuplicates(ctx, location.Point, 0)
+		if err != nil {
+			return err
+		}
+
+		house, err = s.houseRpository.SaveHouse(ctx, model.NewHouse{
+			Address:    location.Address,
+			AddressKey: model.NormalizeAddress(location.Address),
+			Developer:  developer,
+			Year:       year,
+			Point:      location.Point,
+		})
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrAlreadyExists) {
 			log.Error("attempt to create invalid house", sl.Err(err))
 			return nil, ErrAddressAlreadyUsed
 		}
+		if errors.Is(err, ErrHouseNearDuplicate) {
+			log.Error("attempt to create near duplicate house", sl.Err(err))
+			return nil, err
+		}
 		log.Error("failed to save house", sl.Err(err))
 		return nil, err
 	}
 
 	return house, nil
 }
+
+// checkNearDuplicates makes sure no house other than the given one
+// is located at nearly the same point. Houses without coordinates are not checked.
+// It locks the house locations, so it must run in the transaction saving the house.
+func (s *Service) checkNearDuplicates(ctx context.Context, point *model.GeoPoint, houseID int64) error {
+	if point == nil {
+		return nil
+	}
+
+	err := s.houseRpository.LockHouseLocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	lower, upper := point.Around(nearDuplicateDistance)
+	houses, err := s.houseRpository.HousesWithin(ctx, lower, upper)
+	if err != nil {
+		return err
+	}
+
+	for _, house := range houses {
+		other := house.Point()
+		if house.ID == houseID || other == nil {
+			continue
+		}
+		if point.DistanceTo(*other) <= nearDuplicateDistance {
+			return fmt.Errorf("%w: house %d at %s", ErrHouseNearDuplicate, house.ID, house.Address)
+		}
+	}
+
+	return nil
+}
+
+// GetHouse retrieves the house along with the number of its approved flats and subscribers.
+func (s *Service) GetHouse(ctx context.Context, ID int64) (*model.HouseSummary, error) {
+	const op = "house.GetHouse"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("house_id", ID),
+	)
+
+	house, err := s.houseRpository.GetHouseSummary(ctx, ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("house does not exist", sl.Err(err))
+			return nil, ErrHouseNotExist
+		}
+		log.Error("failed to get house", sl.Err(err))
+		return nil, err
+	}
+
+	return house, nil
+}
+
+// EditHouse changes the address, developer or year of construction of the house.
+// A new address is geocoded the same way as for a new house.
+func (s *Service) EditHouse(ctx context.Context, ID int64, edit model.HouseEdit) (*model.House, error) {
+	const op = "house.EditHouse"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("house_id", ID),
+	)
+
+	var location *model.GeoAddress
+	if edit.Address != nil {
+		var err error
+		location, err = s.geocoder.Geocode(ctx, *edit.Address)
+		if err != nil {
+			log.Error("failed to geocode address", sl.Err(err))
+			return nil, err
+		}
+	}
+
+	var house *model.House
+	err := s.trManager.Do(ctx, func(ctx context.Context) (err error) {
+		house, err = s.houseRpository.GetHouse(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		house.Edit(edit)
+		if location != nil {
+			house.Locate(location)
+			house.AddressKey = model.NormalizeAddress(house.Address)
+
+			err = s.checkNearDuplicates(ctx, location.Point, house.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		house, err = s.houseRpository.UpdateHouse(ctx, house)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("house does not exist", sl.Err(err))
+			return nil, ErrHouseNotExist
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			log.Error("attempt to move house to used address", sl.Err(err))
+			return nil, ErrAddressAlreadyUsed
+		}
+		log.Error("failed to update house", sl.Err(err))
+		return nil, err
+	}
+
+	return house, nil
+}
+
+// DeleteHouse removes the house together with its flats and subscriptions,
+// discards its pending events and drops everything cached for it.
+// Photo files of the flats are removed once the house is gone.
+func (s *Service) DeleteHouse(ctx context.Context, ID int64) error {
+	const op = "house.DeleteHouse"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("house_id", ID),
+	)
+
+	var (
+		photos                       []*model.FlatPhoto
+		subscriptions, flats, events int64
+	)
+	err := s.trManager.Do(ctx, func(ctx context.Context) (err error) {
+		subscriptions, err = s.subscriptionRepository.DeleteHouseSubscriptions(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		// Photo rows go along with the flats, so their keys are collected beforehand
+		photos, err = s.photoRepository.HouseFlatPhotos(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		flats, err = s.houseRpository.DeleteHouseFlats(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		events, err = s.eventRepository.DiscardHouseEvents(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		return s.houseRpository.DeleteHouse(ctx, ID)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("house does not exist", sl.Err(err))
+			return ErrHouseNotExist
+		}
+		log.Error("failed to delete house", sl.Err(err))
+		return err
+	}
+
+	s.invalidateHouseCache(ID)
+	s.deletePhotoFiles(ctx, log, photos)
+
+	log.Info("house deleted",
+		slog.Int64("flats", flats),
+		slog.Int64("subscriptions", subscriptions),
+		slog.Int64("events", events),
+		slog.Int("photos", len(photos)),
+	)
+
+	return nil
+}
+
+// deletePhotoFiles removes the files of the photos. Failures are only logged
+// since the files are no longer referenced.
+func (s *Service) deletePhotoFiles(ctx context.Context, log *slog.Logger, photos []*model.FlatPhoto) {
+	for _, photo := range photos {
+		for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+			if err := s.storage.Delete(ctx, key); err != nil {
+				log.Error("failed to delete photo file", slog.String("key", key), sl.Err(err))
+			}
+		}
+	}
+}
+
+const (
+	defaultPageSize = int64(20)
+	maxPageSize     = int64(100)
+)
+
+// SearchHouses retrieves a page of houses matching the filter,
+// recently updated houses first unless another order is requested.
+func (s *Service) SearchHouses(ctx context.Context, filter model.HouseFilter) (*model.HousePage, error) {
+	const op = "house.SearchHouses"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	if filter.Sort == "" {
+		filter.Sort = model.SortUpdatedAtDesc
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		filter.Limit = defaultPageSize
+	}
+	limit := filter.Limit
+
+	// Fetch one extra house to find out whether the next page exists
+	filter.Limit++
+	houses, err := s.houseRpository.SearchHouses(ctx, filter)
+	if err != nil {
+		log.Error("failed to search houses", sl.Err(err))
+		return nil, err
+	}
+
+	page := &model.HousePage{Houses: houses}
+	if int64(len(houses)) > limit {
+		page.Houses = houses[:limit]
+		page.NextCursor = filter.Sort.CursorOf(page.Houses[limit-1]).Encode()
+	}
+	if page.Houses == nil {
+		page.Houses = []*model.House{}
+	}
+
+	return page, nil
+}
@@ -2,13 +2,16 @@ package house
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"avito-backend-bootcamp/internal/infra/geocoder"
 	repoErr "avito-backend-bootcamp/internal/infra/repository"
 	"avito-backend-bootcamp/internal/model"
 	repository "avito-backend-bootcamp/internal/service/house/mocks"
@@ -20,8 +23,25 @@ const (
 	testDeveloper = "Acme Developers"
 	testYear      = int64(2024)
 	testID        = int64(3)
+
+	testLeninaAddress = "ул. Ленина, д. 1"
+	testSideAddress   = "456 Side St"
+)
+
+var (
+	testPoint       = model.GeoPoint{Latitude: 40.7128, Longitude: -74.006}
+	testLeninaPoint = model.GeoPoint{Latitude: 55.751244, Longitude: 37.618423}
+	testSidePoint   = model.GeoPoint{Latitude: 40.7306, Longitude: -73.9866}
 )
 
+func newTestGeocoder() *geocoder.File {
+	return geocoder.NewFile([]geocoder.Address{
+		{Address: testAddress, Point: testPoint},
+		{Address: testLeninaAddress, Point: testLeninaPoint},
+		{Address: testSideAddress, Point: testSidePoint},
+	})
+}
+
 type MockHouseRepository struct {
 	ctrl *gomock.Controller
 	mock *repository.MockHouseRepository
@@ -42,11 +62,25 @@ func TestService_CreateHouse(t *testing.T) {
 
 		mockRepo := NewMockHouseRepository(ctrl)
 		mockRepo.mock.EXPECT().
-			SaveHouse(gomock.Any(), testAddress, testDeveloper, testYear).
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			SaveHouse(gomock.Any(), model.NewHouse{
+				Address:    testAddress,
+				AddressKey: "123 main",
+				Developer:  testDeveloper,
+				Year:       testYear,
+				Point:      &testPoint,
+			}).
 			Return(&model.House{ID: testID}, nil)
 
 		s := &Service{
 			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
 			log:            sl.SetupLogger(),
 		}
 
@@ -56,17 +90,137 @@ func TestService_CreateHouse(t *testing.T) {
 		assert.Equal(t, testID, house.ID)
 	})
 
+	t.Run("address is normalized", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			SaveHouse(gomock.Any(), model.NewHouse{
+				Address:    testLeninaAddress,
+				AddressKey: "ленина 1",
+				Developer:  testDeveloper,
+				Year:       testYear,
+				Point:      &testLeninaPoint,
+			}).
+			Return(&model.House{ID: testID, Address: testLeninaAddress}, nil)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		house, err := s.CreateHouse(context.Background(), "Ленина ул 1", testDeveloper, testYear)
+
+		require.NoError(t, err)
+		assert.Equal(t, testLeninaAddress, house.Address)
+	})
+
+	t.Run("address key without geocoder", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			SaveHouse(gomock.Any(), model.NewHouse{
+				Address:    "Ленина ул 1",
+				AddressKey: "ленина 1",
+				Developer:  testDeveloper,
+				Year:       testYear,
+			}).
+			Return(nil, repoErr.ErrAlreadyExists)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       geocoder.Passthrough{},
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.CreateHouse(context.Background(), " Ленина ул  1", testDeveloper, testYear)
+
+		assert.ErrorIs(t, err, ErrAddressAlreadyUsed)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.CreateHouse(context.Background(), "Nowhere 13", testDeveloper, testYear)
+
+		assert.ErrorIs(t, err, model.ErrAddressNotFound)
+	})
+
+	t.Run("near duplicate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// about 10 meters north of the address and about 100 meters east of it
+		nearLatitude, farLongitude := testLeninaPoint.Latitude+0.0001, testLeninaPoint.Longitude+0.0016
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, lower, upper model.GeoPoint) ([]*model.House, error) {
+				assert.Less(t, lower.Latitude, testLeninaPoint.Latitude)
+				assert.Greater(t, upper.Longitude, testLeninaPoint.Longitude)
+				return []*model.House{
+					{ID: 7, Address: "ул. Ленина, д. 3", Latitude: &testLeninaPoint.Latitude, Longitude: &farLongitude},
+					{ID: 8, Address: "ул. Ленина, д. 1А", Latitude: &nearLatitude, Longitude: &testLeninaPoint.Longitude},
+				}, nil
+			})
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.CreateHouse(context.Background(), testLeninaAddress, testDeveloper, testYear)
+
+		assert.ErrorIs(t, err, ErrHouseNearDuplicate)
+		assert.Contains(t, err.Error(), "house 8")
+	})
+
 	t.Run("house already exists", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockRepo := NewMockHouseRepository(ctrl)
 		mockRepo.mock.EXPECT().
-			SaveHouse(gomock.Any(), testAddress, testDeveloper, testYear).
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			SaveHouse(gomock.Any(), gomock.Any()).
 			Return(nil, repoErr.ErrAlreadyExists)
 
 		s := &Service{
 			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
 			log:            sl.SetupLogger(),
 		}
 
@@ -82,11 +236,19 @@ func TestService_CreateHouse(t *testing.T) {
 
 		mockRepo := NewMockHouseRepository(ctrl)
 		mockRepo.mock.EXPECT().
-			SaveHouse(gomock.Any(), testAddress, testDeveloper, testYear).
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			SaveHouse(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("failed to save house"))
 
 		s := &Service{
 			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
 			log:            sl.SetupLogger(),
 		}
 
@@ -96,3 +258,369 @@ func TestService_CreateHouse(t *testing.T) {
 		assert.NotEqual(t, ErrAddressAlreadyUsed, err)
 	})
 }
+
+func TestService_SearchHouses(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{Sort: model.SortUpdatedAtDesc, Limit: defaultPageSize + 1}).
+			Return(nil, nil)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		page, err := s.SearchHouses(context.Background(), model.HouseFilter{})
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.HousePage{Houses: []*model.House{}}, page)
+	})
+
+	t.Run("next page exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		updatedAt := time.Date(2024, time.May, 1, 12, 0, 0, 123456000, time.UTC)
+		houses := []*model.House{
+			{ID: 3, UpdatedAt: updatedAt.Add(time.Hour)},
+			{ID: 2, UpdatedAt: updatedAt},
+			{ID: 1, UpdatedAt: updatedAt},
+		}
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{Developer: testDeveloper, Sort: model.SortUpdatedAtDesc, Limit: 3}).
+			Return(houses, nil)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		page, err := s.SearchHouses(context.Background(), model.HouseFilter{Developer: testDeveloper, Limit: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, houses[:2], page.Houses)
+
+		cursor, err := model.ParseCursor(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, &model.Cursor{Value: updatedAt.UnixMicro(), ID: 2}, cursor)
+	})
+
+	t.Run("feed cursor points at publication time", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		updatedAt := time.Date(2024, time.May, 2, 12, 0, 0, 0, time.UTC)
+		publishedAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+		houses := []*model.House{
+			{ID: 2, UpdatedAt: updatedAt, LastFlatPublishedAt: &publishedAt},
+			{ID: 1, UpdatedAt: updatedAt, LastFlatPublishedAt: &publishedAt},
+		}
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			SearchHouses(gomock.Any(), model.HouseFilter{Sort: model.SortPublishedAtDesc, Limit: 2}).
+			Return(houses, nil)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		page, err := s.SearchHouses(context.Background(), model.HouseFilter{Sort: model.SortPublishedAtDesc, Limit: 1})
+
+		require.NoError(t, err)
+
+		cursor, err := model.ParseCursor(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, &model.Cursor{Value: publishedAt.UnixMicro(), ID: 2}, cursor)
+	})
+
+	t.Run("error searching houses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			SearchHouses(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("failed to search houses"))
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.SearchHouses(context.Background(), model.HouseFilter{})
+
+		assert.Error(t, err)
+	})
+}
+
+func newTestTrManager(ctrl *gomock.Controller) *repository.MockTrManager {
+	trManager := repository.NewMockTrManager(ctrl)
+	trManager.EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	return trManager
+}
+
+func TestService_EditHouse(t *testing.T) {
+	newAddress := "456 Side Street"
+	noDeveloper := ""
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			GetHouse(gomock.Any(), testID).
+			Return(&model.House{
+				ID:                 testID,
+				Address:            testAddress,
+				Developer:          sql.NullString{String: testDeveloper, Valid: true},
+				YearOfConstruction: testYear,
+			}, nil)
+		mockRepo.mock.EXPECT().
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*model.House{{ID: testID, Latitude: &testSidePoint.Latitude, Longitude: &testSidePoint.Longitude}}, nil)
+		mockRepo.mock.EXPECT().
+			UpdateHouse(gomock.Any(), &model.House{
+				ID:                 testID,
+				Address:            testSideAddress,
+				AddressKey:         "456 side",
+				YearOfConstruction: testYear,
+				Latitude:           &testSidePoint.Latitude,
+				Longitude:          &testSidePoint.Longitude,
+			}).
+			DoAndReturn(func(_ context.Context, house *model.House) (*model.House, error) {
+				return house, nil
+			})
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		house, err := s.EditHouse(context.Background(), testID, model.HouseEdit{
+			Address:   &newAddress,
+			Developer: &noDeveloper,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, testSideAddress, house.Address)
+		assert.False(t, house.Developer.Valid)
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			GetHouse(gomock.Any(), testID).
+			Return(nil, repoErr.ErrNotFound)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.EditHouse(context.Background(), testID, model.HouseEdit{Address: &newAddress})
+
+		assert.ErrorIs(t, err, ErrHouseNotExist)
+	})
+
+	t.Run("address already used", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			GetHouse(gomock.Any(), testID).
+			Return(&model.House{ID: testID, Address: testAddress}, nil)
+		mockRepo.mock.EXPECT().
+			LockHouseLocations(gomock.Any()).
+			Return(nil)
+		mockRepo.mock.EXPECT().
+			HousesWithin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			UpdateHouse(gomock.Any(), gomock.Any()).
+			Return(nil, repoErr.ErrAlreadyExists)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			geocoder:       newTestGeocoder(),
+			trManager:      newTestTrManager(ctrl),
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.EditHouse(context.Background(), testID, model.HouseEdit{Address: &newAddress})
+
+		assert.ErrorIs(t, err, ErrAddressAlreadyUsed)
+	})
+}
+
+func TestService_DeleteHouse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		subscriptionRepo := repository.NewMockSubscriptionRepository(ctrl)
+		photoRepo := repository.NewMockFlatPhotoRepository(ctrl)
+		eventRepo := repository.NewMockEventRepository(ctrl)
+		storage := repository.NewMockBlobStorage(ctrl)
+		cache := repository.NewMockCache(ctrl)
+		gomock.InOrder(
+			subscriptionRepo.EXPECT().
+				DeleteHouseSubscriptions(gomock.Any(), testID).
+				Return(int64(2), nil),
+			photoRepo.EXPECT().
+				HouseFlatPhotos(gomock.Any(), testID).
+				Return([]*model.FlatPhoto{{ID: 1, FlatID: 5, Key: "flats/5/a.jpg", ThumbnailKey: "flats/5/a_thumb.jpg"}}, nil),
+			mockRepo.mock.EXPECT().
+				DeleteHouseFlats(gomock.Any(), testID).
+				Return(int64(5), nil),
+			eventRepo.EXPECT().
+				DiscardHouseEvents(gomock.Any(), testID).
+				Return(int64(1), nil),
+			mockRepo.mock.EXPECT().
+				DeleteHouse(gomock.Any(), testID).
+				Return(nil),
+			storage.EXPECT().
+				Delete(gomock.Any(), "flats/5/a.jpg").
+				Return(nil),
+			storage.EXPECT().
+				Delete(gomock.Any(), "flats/5/a_thumb.jpg").
+				Return(errors.New("file is gone")),
+		)
+
+		var removed []string
+		cache.EXPECT().
+			RemoveFunc(gomock.Any()).
+			Do(func(fn func(key string) bool) {
+				for _, key := range []string{"house:3:id_asc:20:", "house:3:stats", "house:33:stats"} {
+					if fn(key) {
+						removed = append(removed, key)
+					}
+				}
+			})
+
+		s := &Service{
+			houseRpository:         mockRepo.mock,
+			subscriptionRepository: subscriptionRepo,
+			photoRepository:        photoRepo,
+			eventRepository:        eventRepo,
+			storage:                storage,
+			cache:                  cache,
+			trManager:              newTestTrManager(ctrl),
+			log:                    sl.SetupLogger(),
+		}
+
+		err := s.DeleteHouse(context.Background(), testID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"house:3:id_asc:20:", "house:3:stats"}, removed)
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		subscriptionRepo := repository.NewMockSubscriptionRepository(ctrl)
+		photoRepo := repository.NewMockFlatPhotoRepository(ctrl)
+		eventRepo := repository.NewMockEventRepository(ctrl)
+		subscriptionRepo.EXPECT().
+			DeleteHouseSubscriptions(gomock.Any(), testID).
+			Return(int64(0), nil)
+		photoRepo.EXPECT().
+			HouseFlatPhotos(gomock.Any(), testID).
+			Return(nil, nil)
+		mockRepo.mock.EXPECT().
+			DeleteHouseFlats(gomock.Any(), testID).
+			Return(int64(0), nil)
+		eventRepo.EXPECT().
+			DiscardHouseEvents(gomock.Any(), testID).
+			Return(int64(0), nil)
+		mockRepo.mock.EXPECT().
+			DeleteHouse(gomock.Any(), testID).
+			Return(repoErr.ErrNotFound)
+
+		s := &Service{
+			houseRpository:         mockRepo.mock,
+			subscriptionRepository: subscriptionRepo,
+			photoRepository:        photoRepo,
+			eventRepository:        eventRepo,
+			trManager:              newTestTrManager(ctrl),
+			log:                    sl.SetupLogger(),
+		}
+
+		err := s.DeleteHouse(context.Background(), testID)
+
+		assert.ErrorIs(t, err, ErrHouseNotExist)
+	})
+}
+
+func TestService_GetHouse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		summary := &model.HouseSummary{
+			House:         model.House{ID: testID, Address: testAddress},
+			ApprovedFlats: 4,
+			Subscribers:   2,
+		}
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			GetHouseSummary(gomock.Any(), testID).
+			Return(summary, nil)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		house, err := s.GetHouse(context.Background(), testID)
+
+		require.NoError(t, err)
+		assert.Equal(t, summary, house)
+	})
+
+	t.Run("house not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockHouseRepository(ctrl)
+		mockRepo.mock.EXPECT().
+			GetHouseSummary(gomock.Any(), testID).
+			Return(nil, repoErr.ErrNotFound)
+
+		s := &Service{
+			houseRpository: mockRepo.mock,
+			log:            sl.SetupLogger(),
+		}
+
+		_, err := s.GetHouse(context.Background(), testID)
+
+		assert.ErrorIs(t, err, ErrHouseNotExist)
+	})
+}
@@ -0,0 +1,158 @@
+package premoderation
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	rulePriceBounds = "price_bounds"
+	ruleBannedWords = "banned_words"
+	ruleDuplicates  = "duplicates"
+)
+
+type FlatRepository interface {
+	FindDuplicateFlats(ctx context.Context, flat *model.Flat) ([]int64, error)
+}
+
+// Engine applies the premoderation rules to flats before moderators see them.
+type Engine struct {
+	rules          *Rules
+	bannedWords    map[string]struct{}
+	flatRepository FlatRepository
+}
+
+func New(rules *Rules, flatRepository FlatRepository) *Engine {
+	bannedWords := make(map[string]struct{}, len(rules.BannedWords.Words))
+	for _, word := range rules.BannedWords.Words {
+		bannedWords[strings.ToLower(word)] = struct{}{}
+	}
+
+	return &Engine{
+		rules:          rules,
+		bannedWords:    bannedWords,
+		flatRepository: flatRepository,
+	}
+}
+
+// Evaluate applies every enabled rule to the flat and returns the decisions
+// of the rules that matched, or a single pass decision if none did.
+func (e *Engine) Evaluate(ctx context.Context, flat *model.Flat) ([]*model.RuleDecision, error) {
+	var decisions []*model.RuleDecision
+
+	if details, ok := e.checkPrice(flat); ok {
+		decisions = append(decisions, newDecision(flat, rulePriceBounds, e.rules.PriceBounds.Action, model.ReasonInvalidPrice, details))
+	}
+
+	if details, ok := e.checkDescription(flat); ok {
+		decisions = append(decisions, newDecision(flat, ruleBannedWords, e.rules.BannedWords.Action, model.ReasonProhibitedContent, details))
+	}
+
+	details, ok, err := e.checkDuplicates(ctx, flat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	if ok {
+		decisions = append(decisions, newDecision(flat, ruleDuplicates, e.rules.Duplicates.Action, model.ReasonDuplicate, details))
+	}
+
+	if len(decisions) == 0 {
+		decisions = append(decisions, &model.RuleDecision{FlatID: flat.ID, Action: model.RuleActionPass})
+	}
+
+	return decisions, nil
+}
+
+func newDecision(flat *model.Flat, rule string, action model.RuleAction, reason model.DeclineReason, details string) *model.RuleDecision {
+	return &model.RuleDecision{
+		FlatID:  flat.ID,
+		Rule:    &rule,
+		Action:  action,
+		Reason:  &reason,
+		Details: &details,
+	}
+}
+
+// checkPrice reports whether the price is out of the bounds for the rooms of the flat.
+func (e *Engine) checkPrice(flat *model.Flat) (string, bool) {
+	if e.rules.PriceBounds.Action == "" {
+		return "", false
+	}
+
+	var bound *PriceBound
+	for i, b := range e.rules.PriceBounds.Bounds {
+		if b.Rooms == flat.Rooms {
+			bound = &e.rules.PriceBounds.Bounds[i]
+			break
+		}
+		if b.Rooms == 0 && bound == nil {
+			bound = &e.rules.PriceBounds.Bounds[i]
+		}
+	}
+	if bound == nil {
+		return "", false
+	}
+
+	if bound.Min > 0 && flat.Price < bound.Min {
+		return fmt.Sprintf("price %d is below the minimum of %d for %d rooms", flat.Price, bound.Min, flat.Rooms), true
+	}
+	if bound.Max > 0 && flat.Price > bound.Max {
+		return fmt.Sprintf("price %d is above the maximum of %d for %d rooms", flat.Price, bound.Max, flat.Rooms), true
+	}
+
+	return "", false
+}
+
+// checkDescription reports whether the description contains banned words.
+func (e *Engine) checkDescription(flat *model.Flat) (string, bool) {
+	if e.rules.BannedWords.Action == "" || flat.Description == nil || len(e.bannedWords) == 0 {
+		return "", false
+	}
+
+	var found []string
+	seen := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(*flat.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if _, banned := e.bannedWords[word]; !banned {
+			continue
+		}
+		if _, dup := seen[word]; dup {
+			continue
+		}
+		seen[word] = struct{}{}
+		found = append(found, word)
+	}
+
+	if len(found) == 0 {
+		return "", false
+	}
+
+	return "description contains banned words: " + strings.Join(found, ", "), true
+}
+
+// checkDuplicates reports whether the same flat is already listed in the house.
+func (e *Engine) checkDuplicates(ctx context.Context, flat *model.Flat) (string, bool, error) {
+	if e.rules.Duplicates.Action == "" {
+		return "", false, nil
+	}
+
+	ids, err := e.flatRepository.FindDuplicateFlats(ctx, flat)
+	if err != nil {
+		return "", false, err
+	}
+	if len(ids) == 0 {
+		return "", false, nil
+	}
+
+	refs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, fmt.Sprint(id))
+	}
+
+	return "same as flats " + strings.Join(refs, ", ") + " of the house", true, nil
+}
@@ -0,0 +1,151 @@
+package premoderation
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	mock "avito-backend-bootcamp/internal/service/premoderation/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRules() *Rules {
+	return &Rules{
+		PriceBounds: PriceBoundsRule{
+			Action: model.RuleActionDecline,
+			Bounds: []PriceBound{
+				{Rooms: 0, Min: 100000},
+				{Rooms: 2, Min: 200000, Max: 1000000},
+			},
+		},
+		BannedWords: BannedWordsRule{
+			Action: model.RuleActionFlag,
+			Words:  []string{"Scam"},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	description := "Cozy flat, no SCAM, no scam at all"
+
+	tests := []struct {
+		name    string
+		flat    *model.Flat
+		rule    *string
+		action  model.RuleAction
+		reason  *model.DeclineReason
+		details *string
+	}{
+		{
+			name:   "pass",
+			flat:   &model.Flat{ID: 1, Rooms: 2, Price: 500000},
+			action: model.RuleActionPass,
+		},
+		{
+			name:    "price above bound for rooms",
+			flat:    &model.Flat{ID: 1, Rooms: 2, Price: 2000000},
+			rule:    ptr(rulePriceBounds),
+			action:  model.RuleActionDecline,
+			reason:  ptr(model.ReasonInvalidPrice),
+			details: ptr("price 2000000 is above the maximum of 1000000 for 2 rooms"),
+		},
+		{
+			name:    "price below fallback bound",
+			flat:    &model.Flat{ID: 1, Rooms: 5, Price: 1000},
+			rule:    ptr(rulePriceBounds),
+			action:  model.RuleActionDecline,
+			reason:  ptr(model.ReasonInvalidPrice),
+			details: ptr("price 1000 is below the minimum of 100000 for 5 rooms"),
+		},
+		{
+			name:    "banned words ignoring case",
+			flat:    &model.Flat{ID: 1, Rooms: 2, Price: 500000, FlatAttributes: model.FlatAttributes{Description: &description}},
+			rule:    ptr(ruleBannedWords),
+			action:  model.RuleActionFlag,
+			reason:  ptr(model.ReasonProhibitedContent),
+			details: ptr("description contains banned words: scam"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := New(newTestRules(), nil)
+
+			decisions, err := engine.Evaluate(context.Background(), tt.flat)
+
+			require.NoError(t, err)
+			assert.Equal(t, []*model.RuleDecision{{
+				FlatID:  tt.flat.ID,
+				Rule:    tt.rule,
+				Action:  tt.action,
+				Reason:  tt.reason,
+				Details: tt.details,
+			}}, decisions)
+		})
+	}
+
+	t.Run("duplicates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		flat := &model.Flat{ID: 3, HouseID: 10, Rooms: 2, Price: 500000}
+
+		flatRepository := mock.NewMockFlatRepository(ctrl)
+		flatRepository.
+			EXPECT().
+			FindDuplicateFlats(gomock.Any(), flat).
+			Return([]int64{1, 2}, nil)
+
+		rules := newTestRules()
+		rules.Duplicates.Action = model.RuleActionFlag
+		engine := New(rules, flatRepository)
+
+		decisions, err := engine.Evaluate(context.Background(), flat)
+
+		require.NoError(t, err)
+		require.Len(t, decisions, 1)
+		assert.Equal(t, ruleDuplicates, *decisions[0].Rule)
+		assert.Equal(t, model.RuleActionFlag, decisions[0].Action)
+		assert.Equal(t, model.ReasonDuplicate, *decisions[0].Reason)
+		assert.Equal(t, "same as flats 1, 2 of the house", *decisions[0].Details)
+	})
+
+	t.Run("duplicates lookup failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dbErr := errors.New("connection lost")
+		flatRepository := mock.NewMockFlatRepository(ctrl)
+		flatRepository.
+			EXPECT().
+			FindDuplicateFlats(gomock.Any(), gomock.Any()).
+			Return(nil, dbErr)
+
+		rules := newTestRules()
+		rules.Duplicates.Action = model.RuleActionFlag
+		engine := New(rules, flatRepository)
+
+		_, err := engine.Evaluate(context.Background(), &model.Flat{ID: 3, Rooms: 2, Price: 500000})
+
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestRulesValidate(t *testing.T) {
+	rules := newTestRules()
+	require.NoError(t, rules.validate())
+
+	rules.Duplicates.Action = model.RuleActionPass
+	assert.Error(t, rules.validate())
+
+	rules = newTestRules()
+	rules.PriceBounds.Bounds = append(rules.PriceBounds.Bounds, PriceBound{Rooms: 3, Min: 500, Max: 100})
+	assert.Error(t, rules.validate())
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
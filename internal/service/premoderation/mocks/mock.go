@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/premoderation/engine.go
+
+// Package mock_premoderation is a generated GoMock package.
+package mock_premoderation
+
+import (
+	model "avito-backend-bootcamp/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFlatRepository is a mock of FlatRepository interface.
+type MockFlatRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlatRepositoryMockRecorder
+}
+
+// MockFlatRepositoryMockRecorder is the mock recorder for MockFlatRepository.
+type MockFlatRepositoryMockRecorder struct {
+	mock *MockFlatRepository
+}
+
+// NewMockFlatRepository creates a new mock instance.
+func NewMockFlatRepository(ctrl *gomock.Controller) *MockFlatRepository {
+	mock := &MockFlatRepository{ctrl: ctrl}
+	mock.recorder = &MockFlatRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlatRepository) EXPECT() *MockFlatRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindDuplicateFlats mocks base method.
+func (m *MockFlatRepository) FindDuplicateFlats(ctx context.Context, flat *model.Flat) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicateFlats", ctx, flat)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicateFlats indicates an expected call of FindDuplicateFlats.
+func (mr *MockFlatRepositoryMockRecorder) FindDuplicateFlats(ctx, flat interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicateFlats", reflect.TypeOf((*MockFlatRepository)(nil).FindDuplicateFlats), ctx, flat)
+}
@@ -0,0 +1,76 @@
+package premoderation
+
+import (
+	"avito-backend-bootcamp/internal/model"
+	"fmt"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// Rules configures the premoderation. A rule without an action is disabled.
+type Rules struct {
+	PriceBounds PriceBoundsRule `yaml:"price_bounds"`
+	BannedWords BannedWordsRule `yaml:"banned_words"`
+	Duplicates  DuplicatesRule  `yaml:"duplicates"`
+}
+
+// PriceBoundsRule checks the price against the bounds for the number of rooms.
+// The bound with zero rooms applies to flats without a bound of their own.
+type PriceBoundsRule struct {
+	Action model.RuleAction `yaml:"action"`
+	Bounds []PriceBound     `yaml:"bounds"`
+}
+
+// PriceBound limits the price of flats with the given number of rooms.
+// Zero limits are not checked.
+type PriceBound struct {
+	Rooms int64 `yaml:"rooms"`
+	Min   int64 `yaml:"min"`
+	Max   int64 `yaml:"max"`
+}
+
+// BannedWordsRule looks for the words in the flat description, ignoring case.
+type BannedWordsRule struct {
+	Action model.RuleAction `yaml:"action"`
+	Words  []string         `yaml:"words"`
+}
+
+// DuplicatesRule looks for the same flat listed in the house.
+type DuplicatesRule struct {
+	Action model.RuleAction `yaml:"action"`
+}
+
+// LoadRules reads the rules from the YAML file.
+func LoadRules(path string) (*Rules, error) {
+	var rules Rules
+	if err := cleanenv.ReadConfig(path, &rules); err != nil {
+		return nil, fmt.Errorf("cannot read premoderation rules: %w", err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+func (r *Rules) validate() error {
+	actions := map[string]model.RuleAction{
+		rulePriceBounds: r.PriceBounds.Action,
+		ruleBannedWords: r.BannedWords.Action,
+		ruleDuplicates:  r.Duplicates.Action,
+	}
+	for rule, action := range actions {
+		if action != "" && action != model.RuleActionFlag && action != model.RuleActionDecline {
+			return fmt.Errorf("rule %s: action must be flag or decline, got %s", rule, action)
+		}
+	}
+
+	for _, bound := range r.PriceBounds.Bounds {
+		if bound.Rooms < 0 || bound.Min < 0 || bound.Max < 0 || (bound.Max > 0 && bound.Max < bound.Min) {
+			return fmt.Errorf("rule %s: invalid bound for %d rooms", rulePriceBounds, bound.Rooms)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+ALTER TABLE flats
+  DROP CONSTRAINT IF EXISTS flats_house_id_number_key,
+  DROP CONSTRAINT IF EXISTS flats_number_check,
+  DROP COLUMN IF EXISTS number;
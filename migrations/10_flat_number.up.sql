@@ -0,0 +1,12 @@
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS number BIGINT NULL;
+
+-- existing flats are numbered in the order they were created
+UPDATE flats f
+SET number = n.rn
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY house_id ORDER BY id) AS rn FROM flats) n
+WHERE f.id = n.id;
+
+ALTER TABLE flats
+  ALTER COLUMN number SET NOT NULL,
+  ADD CONSTRAINT flats_number_check CHECK (number > 0),
+  ADD CONSTRAINT flats_house_id_number_key UNIQUE (house_id, number);
@@ -0,0 +1,3 @@
+ALTER TABLE flats DROP COLUMN IF EXISTS previous_price;
+
+DROP TABLE IF EXISTS flat_price_history;
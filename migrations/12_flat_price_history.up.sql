@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS flat_price_history (
+  id BIGSERIAL PRIMARY KEY,
+  flat_id BIGINT NOT NULL,
+  price BIGINT NOT NULL CHECK (price > 0),
+  created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_flat_price_history_flat_id FOREIGN KEY (flat_id) REFERENCES flats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_flat_price_history_flat_id ON flat_price_history (flat_id);
+
+-- the current price of existing flats starts their history
+INSERT INTO flat_price_history (flat_id, price, created_at)
+SELECT id, price, created_at FROM flats;
+
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS previous_price BIGINT NULL;
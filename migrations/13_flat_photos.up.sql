@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS flat_photos (
+  id BIGSERIAL PRIMARY KEY,
+  flat_id BIGINT NOT NULL,
+  key TEXT NOT NULL,
+  thumbnail_key TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size BIGINT NOT NULL CHECK (size > 0),
+  created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_flat_photos_flat_id FOREIGN KEY (flat_id) REFERENCES flats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_flat_photos_flat_id ON flat_photos (flat_id);
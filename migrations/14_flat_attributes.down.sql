@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_flats_total_area;
+
+ALTER TABLE flats
+  DROP CONSTRAINT IF EXISTS flats_kitchen_area_total_area_check,
+  DROP COLUMN IF EXISTS total_area,
+  DROP COLUMN IF EXISTS kitchen_area,
+  DROP COLUMN IF EXISTS floor,
+  DROP COLUMN IF EXISTS description,
+  DROP COLUMN IF EXISTS layout;
+
+DROP TYPE IF EXISTS flat_layout;
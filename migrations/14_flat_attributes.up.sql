@@ -0,0 +1,12 @@
+CREATE TYPE flat_layout AS ENUM ('studio', 'euro_2', 'euro_3', 'euro_4', 'classic', 'open_plan');
+
+-- existing flats have no attributes, so the columns stay nullable
+ALTER TABLE flats
+  ADD COLUMN IF NOT EXISTS total_area NUMERIC(8, 2) NULL CHECK (total_area > 0),
+  ADD COLUMN IF NOT EXISTS kitchen_area NUMERIC(8, 2) NULL CHECK (kitchen_area > 0),
+  ADD COLUMN IF NOT EXISTS floor BIGINT NULL CHECK (floor > 0),
+  ADD COLUMN IF NOT EXISTS description TEXT NULL,
+  ADD COLUMN IF NOT EXISTS layout flat_layout NULL,
+  ADD CONSTRAINT flats_kitchen_area_total_area_check CHECK (kitchen_area <= total_area);
+
+CREATE INDEX IF NOT EXISTS idx_flats_total_area ON flats (total_area);
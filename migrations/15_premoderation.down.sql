@@ -0,0 +1,18 @@
+ALTER TABLE flats DROP COLUMN IF EXISTS flagged;
+
+DROP TABLE IF EXISTS flat_rule_decisions;
+DROP TYPE IF EXISTS rule_action;
+
+-- enum values can not be dropped, so the type is recreated without them
+DELETE FROM flat_status_history WHERE actor_role = 'system';
+
+ALTER TYPE user_role RENAME TO user_role_old;
+CREATE TYPE user_role AS ENUM ('client', 'moderator');
+
+ALTER TABLE users
+  ALTER COLUMN type TYPE user_role USING type::text::user_role;
+
+ALTER TABLE flat_status_history
+  ALTER COLUMN actor_role TYPE user_role USING actor_role::text::user_role;
+
+DROP TYPE user_role_old;
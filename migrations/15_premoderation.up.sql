@@ -0,0 +1,19 @@
+-- automatic decisions are recorded in the status history on behalf of the system
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'system';
+
+CREATE TYPE rule_action AS ENUM ('pass', 'flag', 'decline');
+
+CREATE TABLE IF NOT EXISTS flat_rule_decisions (
+  id BIGSERIAL PRIMARY KEY,
+  flat_id BIGINT NOT NULL,
+  rule TEXT NULL,
+  action rule_action NOT NULL,
+  reason decline_reason NULL,
+  details TEXT NULL,
+  created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_flat_rule_decisions_flat_id FOREIGN KEY (flat_id) REFERENCES flats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_flat_rule_decisions_flat_id ON flat_rule_decisions (flat_id);
+
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+ALTER TABLE flat_status_history DROP COLUMN IF EXISTS reason;
+
+DROP INDEX IF EXISTS idx_flats_moderation_started_at;
+
+ALTER TABLE flats DROP COLUMN IF EXISTS moderation_started_at;
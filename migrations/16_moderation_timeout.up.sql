@@ -0,0 +1,13 @@
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS moderation_started_at TIMESTAMP WITHOUT TIME ZONE NULL;
+
+-- flats already on moderation are timed from their last transition to it
+UPDATE flats f
+SET moderation_started_at = COALESCE(
+  (SELECT MAX(h.created_at) FROM flat_status_history h WHERE h.flat_id = f.id AND h.new_status = 'on_moderation'),
+  NOW()
+)
+WHERE f.status = 'on_moderation';
+
+CREATE INDEX IF NOT EXISTS idx_flats_moderation_started_at ON flats (moderation_started_at) WHERE status = 'on_moderation';
+
+ALTER TABLE flat_status_history ADD COLUMN IF NOT EXISTS reason TEXT NULL;
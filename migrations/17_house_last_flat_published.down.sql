@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_houses_last_flat_published_at;
+
+ALTER TABLE houses DROP COLUMN IF EXISTS last_flat_published_at;
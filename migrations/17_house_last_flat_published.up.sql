@@ -0,0 +1,16 @@
+ALTER TABLE houses ADD COLUMN IF NOT EXISTS last_flat_published_at TIMESTAMP WITHOUT TIME ZONE NULL;
+
+-- houses are dated by the last approval of their flats recorded so far
+UPDATE houses h
+SET last_flat_published_at = p.published_at,
+    updated_at = GREATEST(h.updated_at, p.published_at)
+FROM (
+  SELECT f.house_id, MAX(s.created_at) AS published_at
+  FROM flat_status_history s
+  JOIN flats f ON f.id = s.flat_id
+  WHERE s.new_status = 'approved'
+  GROUP BY f.house_id
+) p
+WHERE p.house_id = h.id;
+
+CREATE INDEX IF NOT EXISTS idx_houses_last_flat_published_at ON houses (last_flat_published_at, id) WHERE last_flat_published_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_houses_coordinates;
+
+ALTER TABLE houses DROP COLUMN IF EXISTS longitude;
+ALTER TABLE houses DROP COLUMN IF EXISTS latitude;
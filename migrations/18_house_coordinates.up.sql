@@ -0,0 +1,4 @@
+ALTER TABLE houses ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION NULL;
+ALTER TABLE houses ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION NULL;
+
+CREATE INDEX IF NOT EXISTS idx_houses_coordinates ON houses (latitude, longitude) WHERE latitude IS NOT NULL;
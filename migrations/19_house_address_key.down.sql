@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_houses_address_key;
+
+ALTER TABLE houses DROP COLUMN IF EXISTS address_key;
@@ -0,0 +1,23 @@
+-- normalized address: lower case, no punctuation and street type
+-- abbreviations, abbreviated building parts, words in their original order
+-- (the same as model.NormalizeAddress)
+ALTER TABLE houses ADD COLUMN IF NOT EXISTS address_key TEXT NULL;
+
+UPDATE houses h
+SET address_key = (
+  SELECT COALESCE(string_agg(
+    CASE w.word WHEN 'корпус' THEN 'корп' WHEN 'строение' THEN 'стр' ELSE w.word END,
+    ' ' ORDER BY w.pos), '')
+  FROM regexp_split_to_table(replace(lower(h.address), 'ё', 'е'), '[^[:alnum:]]+') WITH ORDINALITY AS w(word, pos)
+  WHERE w.word <> '' AND w.word NOT IN ('г', 'город', 'ул', 'улица', 'д', 'дом', 'street', 'st')
+);
+
+-- houses registered twice before normalization keep distinct keys,
+-- only the oldest one is matched by new addresses
+UPDATE houses h
+SET address_key = h.address_key || ' #' || h.id
+WHERE EXISTS (SELECT 1 FROM houses o WHERE o.address_key = h.address_key AND o.id < h.id);
+
+ALTER TABLE houses ALTER COLUMN address_key SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_houses_address_key ON houses (address_key);
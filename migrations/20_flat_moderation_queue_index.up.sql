@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_flats_moderation_queue ON flats (created_at, id) WHERE status = 'created';
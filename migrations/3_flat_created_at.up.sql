@@ -0,0 +1 @@
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW();
@@ -0,0 +1 @@
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS moderator_id UUID NULL;
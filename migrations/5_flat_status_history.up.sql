@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS flat_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  flat_id BIGINT NOT NULL,
+  old_status moderation_status NOT NULL,
+  new_status moderation_status NOT NULL,
+  actor_id UUID NOT NULL,
+  actor_role user_role NOT NULL,
+  created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_flat_status_history_flat_id FOREIGN KEY (flat_id) REFERENCES flats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_flat_status_history_flat_id ON flat_status_history (flat_id);
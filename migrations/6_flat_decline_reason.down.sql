@@ -0,0 +1,5 @@
+ALTER TABLE flats
+  DROP COLUMN IF EXISTS decline_reason,
+  DROP COLUMN IF EXISTS decline_comment;
+
+DROP TYPE IF EXISTS decline_reason;
@@ -0,0 +1,5 @@
+CREATE TYPE decline_reason AS ENUM ('invalid_price', 'invalid_rooms', 'duplicate', 'prohibited_content', 'incomplete_info', 'other');
+
+ALTER TABLE flats
+  ADD COLUMN IF NOT EXISTS decline_reason decline_reason NULL,
+  ADD COLUMN IF NOT EXISTS decline_comment TEXT NULL;
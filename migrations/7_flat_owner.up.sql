@@ -0,0 +1,2 @@
+-- dummy login users are not persisted, so owner_id does not reference users
+ALTER TABLE flats ADD COLUMN IF NOT EXISTS owner_id UUID NULL;
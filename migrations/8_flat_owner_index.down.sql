@@ -0,0 +1 @@
+DROP INDEX IF EXISTS flats_owner_id_idx;
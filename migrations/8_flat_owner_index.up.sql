@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS flats_owner_id_idx ON flats (owner_id, id) WHERE owner_id IS NOT NULL;
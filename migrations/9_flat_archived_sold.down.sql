@@ -0,0 +1,27 @@
+-- enum values can not be dropped, so the types are recreated without them
+DELETE FROM events WHERE type IN ('flat_archived', 'flat_sold');
+DELETE FROM flat_status_history
+  WHERE old_status IN ('archived', 'sold') OR new_status IN ('archived', 'sold');
+UPDATE flats SET status = 'approved' WHERE status IN ('archived', 'sold');
+
+ALTER TYPE moderation_status RENAME TO moderation_status_old;
+CREATE TYPE moderation_status AS ENUM ('created', 'on_moderation', 'approved', 'declined');
+
+ALTER TABLE flats
+  ALTER COLUMN status DROP DEFAULT,
+  ALTER COLUMN status TYPE moderation_status USING status::text::moderation_status,
+  ALTER COLUMN status SET DEFAULT 'created';
+
+ALTER TABLE flat_status_history
+  ALTER COLUMN old_status TYPE moderation_status USING old_status::text::moderation_status,
+  ALTER COLUMN new_status TYPE moderation_status USING new_status::text::moderation_status;
+
+DROP TYPE moderation_status_old;
+
+ALTER TYPE event_type RENAME TO event_type_old;
+CREATE TYPE event_type AS ENUM ('flat_approved');
+
+ALTER TABLE events
+  ALTER COLUMN type TYPE event_type USING type::text::event_type;
+
+DROP TYPE event_type_old;
@@ -0,0 +1,5 @@
+ALTER TYPE moderation_status ADD VALUE IF NOT EXISTS 'archived';
+ALTER TYPE moderation_status ADD VALUE IF NOT EXISTS 'sold';
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'flat_archived';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'flat_sold';
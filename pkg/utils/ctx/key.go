@@ -2,4 +2,5 @@ package ctx
 
 const (
 	KeyUserType = "user_type"
+	KeyUserID   = "user_id"
 )
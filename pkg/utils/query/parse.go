@@ -0,0 +1,76 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Int64 parses an optional int64 query parameter, returning 0 when it is absent.
+func Int64(values url.Values, key string) (int64, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query param %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// Float64 parses an optional float64 query parameter, returning 0 when it is absent.
+func Float64(values url.Values, key string) (float64, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query param %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// Int64List parses a repeated or comma separated int64 query parameter.
+func Int64List(values url.Values, key string) ([]int64, error) {
+	var result []int64
+
+	for _, raw := range values[key] {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			value, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query param %s: %w", key, err)
+			}
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// StringList parses a repeated or comma separated string query parameter.
+func StringList(values url.Values, key string) []string {
+	var result []string
+
+	for _, raw := range values[key] {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+
+	return result
+}